@@ -12,6 +12,7 @@ import (
 	"github.com/dmehra2102/budget-tracker/internal/cache"
 	"github.com/dmehra2102/budget-tracker/internal/config"
 	"github.com/dmehra2102/budget-tracker/internal/database"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
 	"github.com/dmehra2102/budget-tracker/internal/handler"
 	"github.com/dmehra2102/budget-tracker/internal/middleware"
 	"github.com/dmehra2102/budget-tracker/internal/repository"
@@ -34,29 +35,71 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := database.CheckMigrations(context.Background(), db.DB(), cfg); err != nil {
+		log.Fatalf("Database is not migrated: %v", err)
+	}
+
 	redisClient, err := cache.NewRedisClient(cfg)
 	if err != nil {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
 	defer redisClient.Close()
 
-	jwtAuth := utils.NewJWTAuth(cfg.JWT.Secret, cfg.JWT.AccessTokenTTL)
+	jwtAuth := utils.NewJWTAuth(cfg.JWT.Secret, cfg.JWT.AccessTokenTTL, cfg.JWT.RefreshTokenTTL)
 
 	cacheService := cache.NewCacheService(redisClient, cfg)
 
+	uow := repository.NewUnitOfWork(db.DB())
 	userRepo := repository.NewUserRepository(db.DB())
-	// alertRepo := repository.NewAlertRepository(db.DB())
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db.DB())
+	alertRepo := repository.NewAlertRepository(db.DB())
 	budgetRepo := repository.NewBudgetRepository(db.DB())
+	expenseRepo := repository.NewExpenseRepository(db.DB())
+	outboxRepo := repository.NewOutboxRepository(db.DB())
+	recurringRepo := repository.NewRecurringExpenseRepository(db.DB())
+	reconciliationRepo := repository.NewReconciliationRepository(db.DB())
+	exchangeRateRepo := repository.NewExchangeRateRepository(db.DB())
+	templateRepo := repository.NewBudgetTemplateRepository(db.DB())
+	transferRepo := repository.NewEnvelopeTransferRepository(db.DB())
+	invitationRepo := repository.NewBudgetInvitationRepository(db.DB())
+	analyticsRepo := repository.NewAnalyticsRepository(db.DB())
+	goalRepo := repository.NewGoalRepository(db.DB())
+	categoryRepo := repository.NewCategoryRepository(db.DB())
+	notificationRepo := repository.NewNotificationRepository(db.DB())
 
 	emailService := service.NewEmailService(cfg)
-	authService := service.NewAuthService(userRepo, emailService, cfg, jwtAuth)
-	budgetService := service.NewBudgetService(budgetRepo, cacheService)
-	// alertService := service.NewAlertService(alertRepo, budgetRepo, userRepo, emailService)
+	rateProvider := service.NewStoredRateProvider(exchangeRateRepo, domain.Currency(cfg.Currency.RateBase))
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, emailService, cfg, jwtAuth)
+	categoryService := service.NewCategoryService(uow, categoryRepo, budgetRepo, templateRepo, expenseRepo, recurringRepo, goalRepo, cacheService)
+	budgetService := service.NewBudgetService(budgetRepo, templateRepo, categoryService, cacheService, domain.Currency(cfg.Currency.Default))
+	expenseService := service.NewExpenseService(uow, expenseRepo, budgetRepo, categoryRepo, outboxRepo, rateProvider, cacheService)
+	alertService := service.NewAlertService(alertRepo, budgetRepo, userRepo, service.NewNotifiers(cfg, emailService), cfg.Worker.AlertCheckConcurrency)
+	recurringService := service.NewRecurringExpenseService(recurringRepo, budgetRepo, expenseService, categoryService)
+	reconciliationService := service.NewReconciliationService(uow, budgetRepo, expenseRepo, reconciliationRepo, cacheService)
+	templateService := service.NewBudgetTemplateService(templateRepo, categoryService)
+	envelopeService := service.NewEnvelopeService(uow, budgetRepo, transferRepo, cacheService)
+	memberService := service.NewBudgetMemberService(uow, budgetRepo, invitationRepo, userRepo, emailService, cacheService)
+	forecastService := service.NewForecastService(budgetRepo, expenseRepo, recurringRepo, rateProvider)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, budgetRepo, expenseRepo, categoryRepo, cacheService)
+	goalService := service.NewGoalService(uow, goalRepo, userRepo, categoryService, emailService, domain.Currency(cfg.Currency.Default))
+	notificationService := service.NewNotificationService(notificationRepo)
 
 	authHandler := handler.NewAuthHandler(authService)
 	budgetHandler := handler.NewBudgetHandler(budgetService)
+	expenseHandler := handler.NewExpenseHandler(expenseService)
+	alertHandler := handler.NewAlertHandler(alertService)
+	recurringHandler := handler.NewRecurringExpenseHandler(recurringService)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+	templateHandler := handler.NewBudgetTemplateHandler(templateService, budgetService)
+	envelopeHandler := handler.NewEnvelopeHandler(envelopeService)
+	memberHandler := handler.NewBudgetMemberHandler(memberService)
+	forecastHandler := handler.NewForecastHandler(forecastService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	goalHandler := handler.NewGoalHandler(goalService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
 
-	router := setupRouter(jwtAuth, authHandler, budgetHandler)
+	router := setupRouter(jwtAuth, userRepo, authHandler, budgetHandler, expenseHandler, alertHandler, recurringHandler, reconciliationHandler, templateHandler, envelopeHandler, memberHandler, forecastHandler, analyticsHandler, goalHandler, categoryHandler, notificationHandler)
 
 	// Create server
 	srv := &http.Server{
@@ -92,9 +135,21 @@ func main() {
 
 func setupRouter(
 	jwtAuth *utils.JWTAuth,
+	userRepo repository.UserRepository,
 	authHandler *handler.AuthHandler,
 	budgetHandler *handler.BudgetHandler,
-
+	expenseHandler *handler.ExpenseHandler,
+	alertHandler *handler.AlertHandler,
+	recurringHandler *handler.RecurringExpenseHandler,
+	reconciliationHandler *handler.ReconciliationHandler,
+	templateHandler *handler.BudgetTemplateHandler,
+	envelopeHandler *handler.EnvelopeHandler,
+	memberHandler *handler.BudgetMemberHandler,
+	forecastHandler *handler.ForecastHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	goalHandler *handler.GoalHandler,
+	categoryHandler *handler.CategoryHandler,
+	notificationHandler *handler.NotificationHandler,
 ) *mux.Router {
 	router := mux.NewRouter()
 
@@ -110,18 +165,109 @@ func setupRouter(
 	// Public routes
 	router.HandleFunc("/api/v1/auth/register", authHandler.Register).Methods("POST")
 	router.HandleFunc("/api/v1/auth/login", authHandler.Login).Methods("POST")
+	router.HandleFunc("/api/v1/auth/refresh", authHandler.Refresh).Methods("POST")
+	router.HandleFunc("/api/v1/auth/logout", authHandler.Logout).Methods("POST")
 	router.HandleFunc("/api/v1/auth/forgot-password", authHandler.ForgotPassword).Methods("POST")
 	router.HandleFunc("/api/v1/auth/reset-password", authHandler.ResetPassword).Methods("POST")
 
 	protected := router.PathPrefix("/api/v1").Subrouter()
 	protected.Use(middleware.AuthMiddleware(jwtAuth))
 
+	// Category routes
+	protected.HandleFunc("/categories", categoryHandler.CreateCategory).Methods("POST")
+	protected.HandleFunc("/categories", categoryHandler.GetCategories).Methods("GET")
+	protected.HandleFunc("/categories/{id}", categoryHandler.GetCategory).Methods("GET")
+	protected.HandleFunc("/categories/{id}", categoryHandler.UpdateCategory).Methods("PUT")
+
 	// Budget routes
 	protected.HandleFunc("/budgets", budgetHandler.CreateBudget).Methods("POST")
 	protected.HandleFunc("/budgets", budgetHandler.GetBudgets).Methods("GET")
 	protected.HandleFunc("/budgets/{id}", budgetHandler.GetBudget).Methods("GET")
 	protected.HandleFunc("/budgets/{id}", budgetHandler.UpdateBudget).Methods("PUT")
+	protected.HandleFunc("/budgets/{id}", budgetHandler.PatchBudget).Methods("PATCH")
 	protected.HandleFunc("/budgets/{id}", budgetHandler.DeleteBudget).Methods("DELETE")
+	protected.HandleFunc("/budgets/{id}/clone", budgetHandler.CloneBudget).Methods("POST")
+	protected.HandleFunc("/budgets/{id}/expenses", expenseHandler.GetBudgetExpenses).Methods("GET")
+	protected.HandleFunc("/budgets/{id}/alerts", alertHandler.GetBudgetAlerts).Methods("GET")
+	protected.HandleFunc("/budgets/{id}/forecast", forecastHandler.GetForecast).Methods("GET")
+	protected.HandleFunc("/budgets/{id}/budget-vs-actual", analyticsHandler.GetBudgetVsActual).Methods("GET")
+
+	// Envelope budget routes
+	protected.HandleFunc("/budgets/{id}/income", envelopeHandler.RecordIncome).Methods("POST")
+	protected.HandleFunc("/budgets/{id}/envelopes", envelopeHandler.GetEnvelopes).Methods("GET")
+	protected.HandleFunc("/budgets/{id}/transfers", envelopeHandler.CreateTransfer).Methods("POST")
+	protected.HandleFunc("/budgets/{id}/transfers", envelopeHandler.GetTransfers).Methods("GET")
+
+	// Budget sharing routes
+	protected.HandleFunc("/budgets/{id}/members", memberHandler.GetMembers).Methods("GET")
+	protected.HandleFunc("/budgets/{id}/members/{userId}", memberHandler.UpdateMember).Methods("PUT")
+	protected.HandleFunc("/budgets/{id}/members/{userId}", memberHandler.RemoveMember).Methods("DELETE")
+	protected.HandleFunc("/budgets/{id}/invitations", memberHandler.InviteMember).Methods("POST")
+	protected.HandleFunc("/budgets/{id}/invitations", memberHandler.GetBudgetInvitations).Methods("GET")
+	protected.HandleFunc("/budgets/{id}/invitations/{invitationId}", memberHandler.RevokeInvitation).Methods("DELETE")
+	protected.HandleFunc("/invitations", memberHandler.GetInvitations).Methods("GET")
+	protected.HandleFunc("/invitations/accept", memberHandler.AcceptInvitation).Methods("POST")
+
+	// Budget template routes
+	protected.HandleFunc("/budget-templates", templateHandler.CreateTemplate).Methods("POST")
+	protected.HandleFunc("/budget-templates", templateHandler.GetTemplates).Methods("GET")
+	protected.HandleFunc("/budget-templates/{id}", templateHandler.GetTemplate).Methods("GET")
+	protected.HandleFunc("/budget-templates/{id}", templateHandler.UpdateTemplate).Methods("PUT")
+	protected.HandleFunc("/budget-templates/{id}", templateHandler.DeleteTemplate).Methods("DELETE")
+	protected.HandleFunc("/budget-templates/{id}/budgets", templateHandler.CreateBudget).Methods("POST")
+
+	// Expense routes
+	protected.HandleFunc("/expenses", expenseHandler.CreateExpense).Methods("POST")
+	protected.HandleFunc("/expenses", expenseHandler.GetExpenses).Methods("GET")
+	protected.HandleFunc("/expenses/{id}", expenseHandler.GetExpense).Methods("GET")
+	protected.HandleFunc("/expenses/{id}", expenseHandler.UpdateExpense).Methods("PUT")
+	protected.HandleFunc("/expenses/{id}", expenseHandler.PatchExpense).Methods("PATCH")
+	protected.HandleFunc("/expenses/{id}", expenseHandler.DeleteExpense).Methods("DELETE")
+
+	// Recurring expense routes
+	protected.HandleFunc("/recurring-expenses", recurringHandler.CreateRecurringExpense).Methods("POST")
+	protected.HandleFunc("/recurring-expenses", recurringHandler.GetRecurringExpenses).Methods("GET")
+	protected.HandleFunc("/recurring-expenses/{id}", recurringHandler.GetRecurringExpense).Methods("GET")
+	protected.HandleFunc("/recurring-expenses/{id}", recurringHandler.UpdateRecurringExpense).Methods("PUT")
+	protected.HandleFunc("/recurring-expenses/{id}", recurringHandler.DeleteRecurringExpense).Methods("DELETE")
+
+	// Goal routes
+	protected.HandleFunc("/goals", goalHandler.CreateGoal).Methods("POST")
+	protected.HandleFunc("/goals", goalHandler.GetGoals).Methods("GET")
+	protected.HandleFunc("/goals/{id}", goalHandler.GetGoal).Methods("GET")
+	protected.HandleFunc("/goals/{id}", goalHandler.UpdateGoal).Methods("PUT")
+	protected.HandleFunc("/goals/{id}", goalHandler.DeleteGoal).Methods("DELETE")
+	protected.HandleFunc("/goals/{id}/contributions", goalHandler.AddContribution).Methods("POST")
+	protected.HandleFunc("/goals/{id}/contributions", goalHandler.GetContributions).Methods("GET")
+	protected.HandleFunc("/goals/{id}/contributions/{contributionId}", goalHandler.DeleteContribution).Methods("DELETE")
+
+	// Analytics routes
+	protected.HandleFunc("/analytics/spending/categories", analyticsHandler.GetSpendByCategory).Methods("GET")
+	protected.HandleFunc("/analytics/spending/timeline", analyticsHandler.GetSpendOverTime).Methods("GET")
+	protected.HandleFunc("/analytics/spending/top-descriptions", analyticsHandler.GetTopDescriptions).Methods("GET")
+
+	// Alert routes
+	protected.HandleFunc("/alerts", alertHandler.CreateAlert).Methods("POST")
+	protected.HandleFunc("/alerts", alertHandler.GetAlerts).Methods("GET")
+	protected.HandleFunc("/alerts/{id}", alertHandler.GetAlert).Methods("GET")
+	protected.HandleFunc("/alerts/{id}", alertHandler.UpdateAlert).Methods("PUT")
+	protected.HandleFunc("/alerts/{id}", alertHandler.DeleteAlert).Methods("DELETE")
+	protected.HandleFunc("/alerts/{id}/enable", alertHandler.EnableAlert).Methods("POST")
+	protected.HandleFunc("/alerts/{id}/disable", alertHandler.DisableAlert).Methods("POST")
+
+	// Notification inbox routes
+	protected.HandleFunc("/notifications", notificationHandler.GetNotifications).Methods("GET")
+	protected.HandleFunc("/notifications/unread-count", notificationHandler.GetUnreadCount).Methods("GET")
+	protected.HandleFunc("/notifications/read", notificationHandler.MarkAllRead).Methods("POST")
+	protected.HandleFunc("/notifications/unread", notificationHandler.MarkAllUnread).Methods("POST")
+	protected.HandleFunc("/notifications/{id}/read", notificationHandler.MarkRead).Methods("POST")
+	protected.HandleFunc("/notifications/{id}/unread", notificationHandler.MarkUnread).Methods("POST")
+
+	// Admin routes
+	admin := protected.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.AdminMiddleware(userRepo))
+	admin.HandleFunc("/reconciliations", reconciliationHandler.RunReconciliation).Methods("POST")
+	admin.HandleFunc("/reconciliations", reconciliationHandler.GetReports).Methods("GET")
 
 	// Health check
 	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/database"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewMongoDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer db.Close()
+
+	applied, err := database.RunMigrations(context.Background(), db.DB(), cfg)
+	if err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	if len(applied) == 0 {
+		log.Println("Database is up to date")
+		return
+	}
+	log.Printf("Applied %d migration(s)", len(applied))
+}
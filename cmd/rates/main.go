@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/database"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"github.com/dmehra2102/budget-tracker/pkg/ecb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// rates loads an ECB reference rate file into the exchange_rates collection.
+func main() {
+	file := flag.String("file", "", "path to an ECB eurofxref XML file")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("Usage: rates -file eurofxref-hist.xml")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Currency.RateBase != ecb.BaseCurrency {
+		log.Fatalf("EXCHANGE_RATE_BASE is %s but ECB rates are quoted against %s", cfg.Currency.RateBase, ecb.BaseCurrency)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open rates file: %v", err)
+	}
+	defer f.Close()
+
+	days, err := ecb.Parse(f)
+	if err != nil {
+		log.Fatalf("Failed to parse rates file: %v", err)
+	}
+
+	var rates []*domain.ExchangeRate
+	for _, day := range days {
+		for _, rate := range day.Rates {
+			value, err := primitive.ParseDecimal128(rate.Value)
+			if err != nil {
+				log.Fatalf("Invalid rate %q for %s on %s", rate.Value, rate.Currency, day.Date.Format(time.DateOnly))
+			}
+			rates = append(rates, &domain.ExchangeRate{
+				Base:   ecb.BaseCurrency,
+				Quote:  domain.Currency(rate.Currency),
+				Rate:   value,
+				Date:   day.Date,
+				Source: "ecb",
+			})
+		}
+	}
+
+	db, err := database.NewMongoDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer db.Close()
+
+	rateRepo := repository.NewExchangeRateRepository(db.DB())
+	changed, err := rateRepo.Upsert(context.Background(), rates)
+	if err != nil {
+		log.Fatalf("Failed to store rates: %v", err)
+	}
+
+	log.Printf("Loaded %d rates for %d days (%d new or changed)", len(rates), len(days), changed)
+}
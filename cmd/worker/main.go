@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"github.com/dmehra2102/budget-tracker/internal/cache"
 	"github.com/dmehra2102/budget-tracker/internal/config"
 	"github.com/dmehra2102/budget-tracker/internal/database"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
 	"github.com/dmehra2102/budget-tracker/internal/repository"
 	"github.com/dmehra2102/budget-tracker/internal/service"
 	"github.com/dmehra2102/budget-tracker/internal/worker"
@@ -25,20 +28,52 @@ func main() {
 	}
 	defer db.Close()
 
+	if err := database.CheckMigrations(context.Background(), db.DB(), cfg); err != nil {
+		log.Fatalf("Database is not migrated: %v", err)
+	}
+
+	redisClient, err := cache.NewRedisClient(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
+
+	cacheService := cache.NewCacheService(redisClient, cfg)
+	locker := cache.NewLocker(redisClient)
+
 	// Initialize repositories
+	uow := repository.NewUnitOfWork(db.DB())
 	userRepo := repository.NewUserRepository(db.DB())
 	budgetRepo := repository.NewBudgetRepository(db.DB())
 	alertRepo := repository.NewAlertRepository(db.DB())
+	expenseRepo := repository.NewExpenseRepository(db.DB())
+	outboxRepo := repository.NewOutboxRepository(db.DB())
+	recurringRepo := repository.NewRecurringExpenseRepository(db.DB())
+	reconciliationRepo := repository.NewReconciliationRepository(db.DB())
+	exchangeRateRepo := repository.NewExchangeRateRepository(db.DB())
+	templateRepo := repository.NewBudgetTemplateRepository(db.DB())
+	goalRepo := repository.NewGoalRepository(db.DB())
+	categoryRepo := repository.NewCategoryRepository(db.DB())
 
 	// Initialize Services
 	emailService := service.NewEmailService(cfg)
-	alertService := service.NewAlertService(alertRepo, budgetRepo, userRepo, emailService)
+	rateProvider := service.NewStoredRateProvider(exchangeRateRepo, domain.Currency(cfg.Currency.RateBase))
+	alertService := service.NewAlertService(alertRepo, budgetRepo, userRepo, service.NewNotifiers(cfg, emailService), cfg.Worker.AlertCheckConcurrency)
+	categoryService := service.NewCategoryService(uow, categoryRepo, budgetRepo, templateRepo, expenseRepo, recurringRepo, goalRepo, cacheService)
+	expenseService := service.NewExpenseService(uow, expenseRepo, budgetRepo, categoryRepo, outboxRepo, rateProvider, cacheService)
+	recurringService := service.NewRecurringExpenseService(recurringRepo, budgetRepo, expenseService, categoryService)
+	reconciliationService := service.NewReconciliationService(uow, budgetRepo, expenseRepo, reconciliationRepo, cacheService)
+	rolloverService := service.NewRolloverService(uow, budgetRepo, templateRepo, alertRepo, cacheService)
+	goalService := service.NewGoalService(uow, goalRepo, userRepo, categoryService, emailService, domain.Currency(cfg.Currency.Default))
+
+	cronWorker := worker.NewCronWorker(cfg, db.DB(), locker, alertService, recurringService, reconciliationService, rolloverService, goalService, budgetRepo)
 
-	cronWorker := worker.NewCronWorker(cfg, db.DB(), alertService, budgetRepo)
+	outboxPoller := worker.NewOutboxPoller(cfg, locker, outboxRepo, alertService)
 
 	if err := cronWorker.Start(); err != nil {
 		log.Fatalf("Failed to start worker: %v", err)
 	}
+	outboxPoller.Start()
 
 	log.Println("Worker Started Successfully")
 
@@ -48,6 +83,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down worker...")
+	outboxPoller.Stop()
 	cronWorker.Stop()
 	log.Println("Worker exited")
 }
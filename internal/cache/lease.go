@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrLeaseHeld = errors.New("lease is held by another instance")
+
+// releaseScript deletes the lease key only while it still holds our token, so
+// an instance whose lease expired cannot release the lease of the instance
+// that took over.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Locker hands out leases: locks shared by every instance through Redis that
+// expire on their own if the holder dies.
+type Locker interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error)
+}
+
+type Lease interface {
+	Release(ctx context.Context) error
+}
+
+type redisLocker struct {
+	client *redis.Client
+}
+
+func NewLocker(client *redis.Client) Locker {
+	return &redisLocker{
+		client: client,
+	}
+}
+
+// Acquire takes the lease on key for ttl, or returns ErrLeaseHeld when
+// another holder has it. The lease is not renewed, so ttl must outlast the
+// work it guards.
+func (l *redisLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lease, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	lease := &redisLease{
+		client: l.client,
+		key:    "lease:" + key,
+		token:  hex.EncodeToString(token),
+	}
+
+	ok, err := l.client.SetNX(ctx, lease.key, lease.token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLeaseHeld
+	}
+	return lease, nil
+}
+
+type redisLease struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+func (l *redisLease) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err()
+}
@@ -10,12 +10,14 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Email    EmailConfig
-	Worker   WorkerConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	Email        EmailConfig
+	Currency     CurrencyConfig
+	Notification NotificationConfig
+	Worker       WorkerConfig
 }
 
 type ServerConfig struct {
@@ -56,11 +58,32 @@ type EmailConfig struct {
 	FromAddress  string
 }
 
+type CurrencyConfig struct {
+	Default  string
+	RateBase string
+}
+
+type NotificationConfig struct {
+	WebhookTimeout time.Duration
+	RetentionDays  int
+}
+
 type WorkerConfig struct {
-	CleanupSchedule    string
-	AlertCheckSchedule string
-	SessionCleanupDays int
-	ExpiredTokenDays   int
+	CleanupSchedule          string
+	AlertCheckSchedule       string
+	AlertCheckConcurrency    int
+	AlertCheckTimeout        time.Duration
+	RecurringExpenseSchedule string
+	ReconcileSchedule        string
+	ReconcileRepair          bool
+	RolloverSchedule         string
+	GoalCheckSchedule        string
+	JobTimeout               time.Duration
+	SessionCleanupDays       int
+	ExpiredTokenDays         int
+	OutboxPollInterval       time.Duration
+	OutboxMaxAttempts        int
+	OutboxRetentionDays      int
 }
 
 func Load() (*Config, error) {
@@ -100,11 +123,30 @@ func Load() (*Config, error) {
 			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
 			FromAddress:  getEnv("EMAIL_FROM", "noreply@budgettracker.com"),
 		},
+		Currency: CurrencyConfig{
+			Default:  getEnv("DEFAULT_CURRENCY", "USD"),
+			RateBase: getEnv("EXCHANGE_RATE_BASE", "EUR"),
+		},
+		Notification: NotificationConfig{
+			WebhookTimeout: getDurationEnv("NOTIFICATION_WEBHOOK_TIMEOUT", 10*time.Second),
+			RetentionDays:  getIntEnv("NOTIFICATION_RETENTION_DAYS", 90),
+		},
 		Worker: WorkerConfig{
-			CleanupSchedule:    getEnv("CLEANUP_SCHEDULE", "0 2 * * *"),        // 2 AM daily
-			AlertCheckSchedule: getEnv("ALERT_CHECK_SCHEDULE", "*/15 * * * *"), // Every 15 min
-			SessionCleanupDays: getIntEnv("SESSION_CLEANUP_DAYS", 30),
-			ExpiredTokenDays:   getIntEnv("EXPIRED_TOKEN_DAYS", 7),
+			CleanupSchedule:          getEnv("CLEANUP_SCHEDULE", "0 2 * * *"),        // 2 AM daily
+			AlertCheckSchedule:       getEnv("ALERT_CHECK_SCHEDULE", "*/15 * * * *"), // Every 15 min
+			AlertCheckConcurrency:    getIntEnv("ALERT_CHECK_CONCURRENCY", 8),
+			AlertCheckTimeout:        getDurationEnv("ALERT_CHECK_TIMEOUT", 10*time.Minute),
+			RecurringExpenseSchedule: getEnv("RECURRING_EXPENSE_SCHEDULE", "0 * * * *"), // Hourly
+			ReconcileSchedule:        getEnv("RECONCILE_SCHEDULE", "30 3 * * *"),        // 3:30 AM daily
+			ReconcileRepair:          getBoolEnv("RECONCILE_REPAIR", false),
+			RolloverSchedule:         getEnv("ROLLOVER_SCHEDULE", "5 * * * *"),   // Hourly at :05
+			GoalCheckSchedule:        getEnv("GOAL_CHECK_SCHEDULE", "0 9 * * *"), // 9 AM daily
+			JobTimeout:               getDurationEnv("JOB_TIMEOUT", 30*time.Minute),
+			SessionCleanupDays:       getIntEnv("SESSION_CLEANUP_DAYS", 30),
+			ExpiredTokenDays:         getIntEnv("EXPIRED_TOKEN_DAYS", 7),
+			OutboxPollInterval:       getDurationEnv("OUTBOX_POLL_INTERVAL", 5*time.Second),
+			OutboxMaxAttempts:        getIntEnv("OUTBOX_MAX_ATTEMPTS", 5),
+			OutboxRetentionDays:      getIntEnv("OUTBOX_RETENTION_DAYS", 7),
 		},
 	}
 
@@ -122,6 +164,21 @@ func (c *Config) Validate() error {
 	if c.JWT.Secret == "your-secret-key" {
 		return fmt.Errorf("JWT_SECRET must be changed in production")
 	}
+	if c.Worker.AlertCheckTimeout <= 0 {
+		return fmt.Errorf("ALERT_CHECK_TIMEOUT must be positive")
+	}
+	if c.Worker.JobTimeout <= 0 {
+		return fmt.Errorf("JOB_TIMEOUT must be positive")
+	}
+	if c.Worker.OutboxPollInterval <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL must be positive")
+	}
+	if c.Notification.RetentionDays < 1 {
+		return fmt.Errorf("NOTIFICATION_RETENTION_DAYS must be at least 1")
+	}
+	if c.Worker.OutboxRetentionDays < 1 {
+		return fmt.Errorf("OUTBOX_RETENTION_DAYS must be at least 1")
+	}
 	return nil
 }
 
@@ -141,6 +198,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
 func getUint64Env(key string, defaultValue uint64) uint64 {
 	if value := os.Getenv(key); value != "" {
 		if uintVal, err := strconv.ParseUint(value, 10, 64); err != nil {
@@ -0,0 +1,563 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const migrationsCollection = "schema_migrations"
+
+// Migration is a one-off data change. Up must be safe to run again if a
+// previous attempt failed part way, since it is only recorded once it succeeds.
+type Migration struct {
+	ID          string
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+type appliedMigration struct {
+	ID          string    `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// migrations returns the migrations in the order they are applied. Append new
+// entries; never reorder or edit ones that have shipped. Settings a migration
+// needs are bound here, so every Up keeps the same signature.
+func migrations(cfg *config.Config) []Migration {
+	return []Migration{
+		{
+			ID:          "0001_money_minor_units",
+			Description: "Convert float64 money fields to int64 minor units",
+			Up:          migrateMoneyToMinorUnits,
+		},
+		{
+			ID:          "0002_default_currency",
+			Description: "Assign the default currency to budgets and expenses",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return migrateDefaultCurrency(ctx, db, domain.Currency(cfg.Currency.Default))
+			},
+		},
+		{
+			ID:          "0003_expense_created_by",
+			Description: "Record the budget owner as the creator of existing expenses",
+			Up:          migrateExpenseCreatedBy,
+		},
+		{
+			ID:          "0004_category_catalog",
+			Description: "Build category catalogs from category names and reference them by ID",
+			Up:          migrateCategoryCatalog,
+		},
+		{
+			ID:          "0005_document_versions",
+			Description: "Start version counters on budgets and expenses",
+			Up:          migrateDocumentVersions,
+		},
+		{
+			ID:          "0006_alert_channels",
+			Description: "Deliver existing alerts by email",
+			Up:          migrateAlertChannels,
+		},
+		{
+			ID:          "0007_alert_tiers",
+			Description: "Turn alert thresholds into single tiers",
+			Up:          migrateAlertTiers,
+		},
+		{
+			ID:          "0008_merge_category_lines",
+			Description: "Merge budget and template lines that share a category",
+			Up:          migrateMergeCategoryLines,
+		},
+		{
+			ID:          "0009_invitation_emails",
+			Description: "Lower-case the email addresses of budget invitations",
+			Up:          migrateInvitationEmails,
+		},
+		{
+			ID:          "0010_notification_channel",
+			Description: "Show notifications recorded before channels existed in the inbox",
+			Up:          migrateNotificationChannel,
+		},
+		{
+			ID:          "0011_goal_categories",
+			Description: "Reference goal categories by catalog ID",
+			Up:          migrateGoalCategories,
+		},
+	}
+}
+
+// CheckMigrations returns an error naming the migrations that have not been
+// applied. The api and worker refuse to start until they are, since their
+// writes assume the current document shapes.
+func CheckMigrations(ctx context.Context, db *mongo.Database, cfg *config.Config) error {
+	collection := db.Collection(migrationsCollection)
+
+	var pending []string
+	for _, migration := range migrations(cfg) {
+		count, err := collection.CountDocuments(ctx, bson.M{"_id": migration.ID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			pending = append(pending, migration.ID)
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations %s, run cmd/migrate first", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// RunMigrations applies every migration that has not been recorded in the
+// schema_migrations collection and returns the IDs it applied.
+func RunMigrations(ctx context.Context, db *mongo.Database, cfg *config.Config) ([]string, error) {
+	collection := db.Collection(migrationsCollection)
+
+	var applied []string
+	for _, migration := range migrations(cfg) {
+		count, err := collection.CountDocuments(ctx, bson.M{"_id": migration.ID})
+		if err != nil {
+			return applied, err
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("Applying migration %s: %s", migration.ID, migration.Description)
+		if err := migration.Up(ctx, db); err != nil {
+			return applied, fmt.Errorf("migration %s: %w", migration.ID, err)
+		}
+
+		_, err = collection.InsertOne(ctx, appliedMigration{
+			ID:          migration.ID,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		})
+		if err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration.ID)
+	}
+
+	return applied, nil
+}
+
+// toMinorUnits converts a legacy double amount into int64 hundredths and
+// leaves values that are already integers untouched.
+func toMinorUnits(field string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": field}, "double"}},
+		bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{field, 100}}, 0}}},
+		field,
+	}}
+}
+
+func isDouble(field string) bson.M {
+	return bson.M{field: bson.M{"$type": "double"}}
+}
+
+func migrateMoneyToMinorUnits(ctx context.Context, db *mongo.Database) error {
+	budgetUpdate := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"total_amount": toMinorUnits("$total_amount"),
+			"spent_amount": toMinorUnits("$spent_amount"),
+			"categories": bson.M{"$map": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$categories", bson.A{}}},
+				"as":    "category",
+				"in": bson.M{"$mergeObjects": bson.A{
+					"$$category",
+					bson.M{
+						"amount":       toMinorUnits("$$category.amount"),
+						"spent_amount": toMinorUnits("$$category.spent_amount"),
+					},
+				}},
+			}},
+		}}},
+	}
+	budgetFilter := bson.M{"$or": bson.A{
+		isDouble("total_amount"),
+		isDouble("spent_amount"),
+		isDouble("categories.amount"),
+		isDouble("categories.spent_amount"),
+	}}
+	if _, err := db.Collection("budgets").UpdateMany(ctx, budgetFilter, budgetUpdate); err != nil {
+		return err
+	}
+
+	fields := []struct {
+		collection string
+		field      string
+	}{
+		{"expenses", "amount"},
+		{"recurring_expenses", "amount"},
+		{"alerts", "threshold"},
+	}
+	for _, f := range fields {
+		update := mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{f.field: toMinorUnits("$" + f.field)}}},
+		}
+		if _, err := db.Collection(f.collection).UpdateMany(ctx, isDouble(f.field), update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateDefaultCurrency treats every budget and expense recorded before
+// currencies existed as being in the configured default currency, so existing
+// expenses were paid in their budget currency at a rate of 1.
+func migrateDefaultCurrency(ctx context.Context, db *mongo.Database, currency domain.Currency) error {
+	missing := bson.M{"currency": bson.M{"$exists": false}}
+
+	_, err := db.Collection("budgets").UpdateMany(ctx, missing, bson.M{"$set": bson.M{"currency": currency}})
+	if err != nil {
+		return err
+	}
+
+	one, err := primitive.ParseDecimal128("1")
+	if err != nil {
+		return err
+	}
+	expenseUpdate := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"currency":          currency,
+			"original_amount":   "$amount",
+			"original_currency": currency,
+			"exchange_rate":     one,
+		}}},
+	}
+	_, err = db.Collection("expenses").UpdateMany(ctx, missing, expenseUpdate)
+	return err
+}
+
+// migrateExpenseCreatedBy fills in created_by on expenses recorded before
+// budgets could be shared, when the only member was the owner.
+func migrateExpenseCreatedBy(ctx context.Context, db *mongo.Database) error {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"created_by": "$user_id"}}},
+	}
+	_, err := db.Collection("expenses").UpdateMany(ctx, bson.M{"created_by": bson.M{"$exists": false}}, update)
+	return err
+}
+
+// migrateCategoryCatalog adds every category name used on a user's budgets,
+// templates, expenses and recurring expenses to the user's catalog, merging
+// names that differ only in case or spacing, and points those documents at
+// the catalog entries. Budgets and expenses belong to the catalog of the
+// budget owner.
+func migrateCategoryCatalog(ctx context.Context, db *mongo.Database) error {
+	catalog := &categoryCatalog{
+		collection: db.Collection("categories"),
+		entries:    make(map[primitive.ObjectID]map[string]catalogEntry),
+	}
+
+	for _, collection := range []string{"budgets", "budget_templates"} {
+		if err := migrateCategoryLines(ctx, db.Collection(collection), catalog); err != nil {
+			return err
+		}
+	}
+
+	missing := bson.M{"category_id": bson.M{"$exists": false}}
+	for _, collection := range []string{"expenses", "recurring_expenses"} {
+		if err := migrateCategoryNames(ctx, db.Collection(collection), catalog, missing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type catalogEntry struct {
+	ID   primitive.ObjectID `bson:"_id"`
+	Name string             `bson:"name"`
+}
+
+// categoryCatalog finds or creates catalog entries by owner and name, and
+// remembers the ones it has seen.
+type categoryCatalog struct {
+	collection *mongo.Collection
+	entries    map[primitive.ObjectID]map[string]catalogEntry
+}
+
+func (c *categoryCatalog) entry(ctx context.Context, userID primitive.ObjectID, name string) (catalogEntry, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Uncategorized"
+	}
+	key := domain.CategoryKey(name)
+
+	if entry, ok := c.entries[userID][key]; ok {
+		return entry, nil
+	}
+
+	now := time.Now()
+	var entry catalogEntry
+	err := c.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"user_id": userID, "key": key},
+		bson.M{"$setOnInsert": bson.M{
+			"name":        name,
+			"is_archived": false,
+			"created_at":  now,
+			"updated_at":  now,
+		}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&entry)
+	if err != nil {
+		return entry, err
+	}
+
+	if c.entries[userID] == nil {
+		c.entries[userID] = make(map[string]catalogEntry)
+	}
+	c.entries[userID][key] = entry
+	return entry, nil
+}
+
+// migrateCategoryLines sets category_id on the category lines of budgets or
+// templates that do not have one yet.
+func migrateCategoryLines(ctx context.Context, collection *mongo.Collection, catalog *categoryCatalog) error {
+	cursor, err := collection.Find(ctx, bson.M{
+		"categories": bson.M{"$elemMatch": bson.M{"category_id": bson.M{"$exists": false}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			UserID     primitive.ObjectID `bson:"user_id"`
+			Categories []bson.M           `bson:"categories"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		set := bson.M{}
+		for i, line := range doc.Categories {
+			if _, ok := line["category_id"]; ok {
+				continue
+			}
+			name, _ := line["name"].(string)
+			entry, err := catalog.entry(ctx, doc.UserID, name)
+			if err != nil {
+				return err
+			}
+			prefix := fmt.Sprintf("categories.%d", i)
+			set[prefix+".category_id"] = entry.ID
+			set[prefix+".name"] = entry.Name
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": set}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// migrateCategoryNames sets category_id on the documents matching missing,
+// which must not have one yet, one owner and category name at a time.
+func migrateCategoryNames(ctx context.Context, collection *mongo.Collection, catalog *categoryCatalog, missing bson.M) error {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: missing}},
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"user_id": "$user_id", "category": "$category"}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var group struct {
+			ID struct {
+				UserID   primitive.ObjectID `bson:"user_id"`
+				Category string             `bson:"category"`
+			} `bson:"_id"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+
+		entry, err := catalog.entry(ctx, group.ID.UserID, group.ID.Category)
+		if err != nil {
+			return err
+		}
+
+		filter := bson.M{
+			"user_id":     group.ID.UserID,
+			"category":    group.ID.Category,
+			"category_id": bson.M{"$exists": false},
+		}
+		update := bson.M{"$set": bson.M{
+			"category_id": entry.ID,
+			"category":    entry.Name,
+		}}
+		if _, err := collection.UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// migrateDocumentVersions sets version 0 on budgets and expenses written
+// before updates were versioned, so the first versioned update matches them.
+func migrateDocumentVersions(ctx context.Context, db *mongo.Database) error {
+	missing := bson.M{"version": bson.M{"$exists": false}}
+	for _, name := range []string{"budgets", "expenses"} {
+		if _, err := db.Collection(name).UpdateMany(ctx, missing, bson.M{"$set": bson.M{"version": 0}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateAlertChannels gives alerts created before channels could be chosen
+// the email channel they were always delivered on.
+func migrateAlertChannels(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("alerts").UpdateMany(
+		ctx,
+		bson.M{"channels": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"channels": []domain.AlertChannel{{Type: domain.NotificationChannelEmail}}}},
+	)
+	return err
+}
+
+// migrateAlertTiers replaces the single threshold of older alerts with a
+// one-tier list and drops the 24-hour throttle, which fired tiers replace.
+func migrateAlertTiers(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("alerts").UpdateMany(
+		ctx,
+		bson.M{"tiers": bson.M{"$exists": false}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"tiers":       bson.A{"$threshold"},
+				"fired_tiers": bson.A{},
+			}}},
+			{{Key: "$unset", Value: bson.A{"threshold", "last_sent_at"}}},
+		},
+	)
+	return err
+}
+
+// migrateMergeCategoryLines merges the category lines of a budget or template
+// that 0004 pointed at the same catalog entry, such as "Food" and "food",
+// into the first of them, adding up their amounts. Spending is only ever
+// charged to the first line with a category, so the lines must be unique.
+func migrateMergeCategoryLines(ctx context.Context, db *mongo.Database) error {
+	for _, name := range []string{"budgets", "budget_templates"} {
+		if err := mergeCategoryLines(ctx, db.Collection(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeCategoryLines(ctx context.Context, collection *mongo.Collection) error {
+	cursor, err := collection.Find(ctx, bson.M{"categories.1": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID         primitive.ObjectID `bson:"_id"`
+			Categories []bson.M           `bson:"categories"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		merged := make([]bson.M, 0, len(doc.Categories))
+		first := make(map[any]bson.M)
+		for _, line := range doc.Categories {
+			kept, ok := first[line["category_id"]]
+			if !ok {
+				first[line["category_id"]] = line
+				merged = append(merged, line)
+				continue
+			}
+			for _, field := range []string{"amount", "spent_amount", "carried_over"} {
+				if _, ok := kept[field]; ok {
+					kept[field] = minorUnits(kept[field]) + minorUnits(line[field])
+				}
+			}
+		}
+		if len(merged) == len(doc.Categories) {
+			continue
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"categories": merged}}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// minorUnits reads a money field written by 0001, which may be missing.
+func minorUnits(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	}
+	return 0
+}
+
+// migrateInvitationEmails lower-cases invitation emails, which are now looked
+// up in lower case.
+func migrateInvitationEmails(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("budget_invitations").UpdateMany(
+		ctx,
+		bson.M{"email": bson.M{"$regex": "[A-Z]"}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"email": bson.M{"$toLower": "$email"}}}},
+		},
+	)
+	return err
+}
+
+// migrateNotificationChannel files the notifications recorded before 0006 as
+// in-app ones, since the inbox only lists in-app notifications and these were
+// the user's only record of the alerts sent.
+func migrateNotificationChannel(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("alert_notifications").UpdateMany(
+		ctx,
+		bson.M{"channel": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"channel": domain.NotificationChannelInApp}},
+	)
+	return err
+}
+
+// migrateGoalCategories points goals that name a category at its catalog
+// entry, creating missing entries as 0004 did. Goals without a category keep
+// none.
+func migrateGoalCategories(ctx context.Context, db *mongo.Database) error {
+	catalog := &categoryCatalog{
+		collection: db.Collection("categories"),
+		entries:    make(map[primitive.ObjectID]map[string]catalogEntry),
+	}
+
+	return migrateCategoryNames(ctx, db.Collection("goals"), catalog, bson.M{
+		"category":    bson.M{"$nin": bson.A{"", nil}},
+		"category_id": bson.M{"$exists": false},
+	})
+}
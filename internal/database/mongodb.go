@@ -84,11 +84,71 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: "is_active", Value: 1}, {Key: "end_date", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "members.user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "categories.category_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "previous_budget_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"previous_budget_id": bson.M{"$exists": true}}),
+		},
 	}
 	if _, err := db.Collection("budgets").Indexes().CreateMany(ctx, budgetIndexes); err != nil {
 		return err
 	}
 
+	// Categories collection indexes
+	categoryIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("categories").Indexes().CreateMany(ctx, categoryIndexes); err != nil {
+		return err
+	}
+
+	// Budget templates collection indexes
+	templateIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	}
+	if _, err := db.Collection("budget_templates").Indexes().CreateMany(ctx, templateIndexes); err != nil {
+		return err
+	}
+
+	// Budget invitations collection indexes
+	invitationIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "budget_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}},
+		},
+	}
+	if _, err := db.Collection("budget_invitations").Indexes().CreateMany(ctx, invitationIndexes); err != nil {
+		return err
+	}
+
+	// Envelope transfers collection indexes
+	transferIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "budget_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+	if _, err := db.Collection("envelope_transfers").Indexes().CreateMany(ctx, transferIndexes); err != nil {
+		return err
+	}
+
 	// Expenses collection indexes
 	expenseIndexes := []mongo.IndexModel{
 		{
@@ -97,6 +157,18 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: "created_by", Value: 1}, {Key: "date", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "category_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "recurring_expense_id", Value: 1}, {Key: "occurrence_date", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"recurring_expense_id": bson.M{"$exists": true}}),
+		},
 	}
 	if _, err := db.Collection("expenses").Indexes().CreateMany(ctx, expenseIndexes); err != nil {
 		return err
@@ -118,5 +190,112 @@ func createIndexes(ctx context.Context, db *mongo.Database) error {
 		return err
 	}
 
+	// Outbox events collection indexes
+	outboxIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "processed_at", Value: 1}, {Key: "occurred_at", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "dead_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
+	}
+	if _, err := db.Collection("outbox_events").Indexes().CreateMany(ctx, outboxIndexes); err != nil {
+		return err
+	}
+
+	// Alert notifications collection indexes
+	notificationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "sent_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "channel", Value: 1}, {Key: "sent_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "channel", Value: 1}, {Key: "read_at", Value: 1}, {Key: "sent_at", Value: -1}},
+		},
+	}
+	if _, err := db.Collection("alert_notifications").Indexes().CreateMany(ctx, notificationIndexes); err != nil {
+		return err
+	}
+
+	// Goals collection indexes
+	goalIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "target_date", Value: 1}},
+		},
+	}
+	if _, err := db.Collection("goals").Indexes().CreateMany(ctx, goalIndexes); err != nil {
+		return err
+	}
+
+	contributionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "goal_id", Value: 1}, {Key: "date", Value: -1}},
+		},
+	}
+	if _, err := db.Collection("goal_contributions").Indexes().CreateMany(ctx, contributionIndexes); err != nil {
+		return err
+	}
+
+	// Recurring expenses collection indexes
+	recurringIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "is_active", Value: 1}, {Key: "next_occurrence", Value: 1}},
+		},
+	}
+	if _, err := db.Collection("recurring_expenses").Indexes().CreateMany(ctx, recurringIndexes); err != nil {
+		return err
+	}
+
+	// Reconciliation reports collection indexes
+	reconciliationIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "started_at", Value: -1}},
+		},
+	}
+	if _, err := db.Collection("reconciliation_reports").Indexes().CreateMany(ctx, reconciliationIndexes); err != nil {
+		return err
+	}
+
+	// Exchange rates collection indexes
+	exchangeRateIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "base", Value: 1}, {Key: "quote", Value: 1}, {Key: "date", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+	if _, err := db.Collection("exchange_rates").Indexes().CreateMany(ctx, exchangeRateIndexes); err != nil {
+		return err
+	}
+
+	// Refresh tokens collection indexes
+	refreshTokenIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+	if _, err := db.Collection("refresh_tokens").Indexes().CreateMany(ctx, refreshTokenIndexes); err != nil {
+		return err
+	}
+
 	return nil
 }
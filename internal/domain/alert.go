@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"sort"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,30 +14,182 @@ const (
 	AlertTypeAmount     AlertType = "amount"
 )
 
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelWebhook NotificationChannel = "webhook"
+	NotificationChannelSlack   NotificationChannel = "slack"
+	NotificationChannelInApp   NotificationChannel = "in_app"
+)
+
+// Alert watches a budget, or one category of it when CategoryID is set.
+// Tiers are thresholds in ascending order: percentages of the limit for
+// percentage alerts and amounts in the budget currency for amount alerts. Each
+// tier fires at most once per budget period; FiredTiers lists those that have
+// fired in the period starting at PeriodStart. When a budget rolls over its
+// alerts move to the next period's budget.
 type Alert struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
-	BudgetID   primitive.ObjectID `bson:"budget_id" json:"budget_id"`
-	Type       AlertType          `bson:"type" json:"type"`
-	Threshold  float64            `bson:"threshold" json:"threshold"`
-	IsEnabled  bool               `bson:"is_enabled" json:"is_enabled"`
-	LastSentAt *time.Time         `bson:"last_sent_at,omitempty" json:"last_sent_at"`
-	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	BudgetID    primitive.ObjectID  `bson:"budget_id" json:"budget_id"`
+	CategoryID  *primitive.ObjectID `bson:"category_id,omitempty" json:"category_id,omitempty"`
+	Type        AlertType           `bson:"type" json:"type"`
+	Tiers       []Money             `bson:"tiers" json:"tiers"`
+	Channels    []AlertChannel      `bson:"channels" json:"channels"`
+	IsEnabled   bool                `bson:"is_enabled" json:"is_enabled"`
+	FiredTiers  []Money             `bson:"fired_tiers" json:"fired_tiers"`
+	PeriodStart *time.Time          `bson:"period_start,omitempty" json:"period_start,omitempty"`
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// maxAlertTiers bounds the tiers of one alert.
+const maxAlertTiers = 10
+
+// NormalizeAlertTiers returns the tiers of an alert request in ascending
+// order. A lone Threshold is a single tier. Tiers must be positive and
+// distinct, and percentage tiers may not exceed 1000%.
+func NormalizeAlertTiers(alertType AlertType, threshold Money, tiers []Money) ([]Money, error) {
+	if len(tiers) == 0 {
+		tiers = []Money{threshold}
+	}
+	if len(tiers) > maxAlertTiers {
+		return nil, ErrInvalidTiers
+	}
+
+	sorted := make([]Money, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for i, tier := range sorted {
+		if tier <= 0 || (i > 0 && tier == sorted[i-1]) {
+			return nil, ErrInvalidTiers
+		}
+		if alertType == AlertTypePercentage && tier.Float64() > 1000 {
+			return nil, ErrInvalidTiers
+		}
+	}
+	return sorted, nil
+}
+
+// ReachedTiers returns the tiers that spent has met. Percentage tiers are
+// measured against total.
+func (a *Alert) ReachedTiers(spent, total Money) []Money {
+	var reached []Money
+	for _, tier := range a.Tiers {
+		switch a.Type {
+		case AlertTypePercentage:
+			if spent.Percent(total) >= tier.Float64() {
+				reached = append(reached, tier)
+			}
+		case AlertTypeAmount:
+			if spent >= tier {
+				reached = append(reached, tier)
+			}
+		}
+	}
+	return reached
 }
 
+// HasFired reports whether tier has fired in the current period.
+func (a *Alert) HasFired(tier Money) bool {
+	for _, fired := range a.FiredTiers {
+		if fired == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertChannel is one place an alert is delivered to. URL is the endpoint of
+// webhook and slack channels. Secret is the key webhook payloads are signed
+// with; it is never returned by the API.
+type AlertChannel struct {
+	Type   NotificationChannel `bson:"type" json:"type"`
+	URL    string              `bson:"url,omitempty" json:"url,omitempty"`
+	Secret string              `bson:"secret,omitempty" json:"-"`
+}
+
+// CreateAlertRequest gives either several Tiers or a single Threshold, and a
+// CategoryID to watch one category of the budget. Channels defaults to email
+// alone. A webhook channel may leave out Secret on update to keep the secret
+// it already has for the same URL. Changing the tiers or scope of an alert
+// lets every tier fire again.
 type CreateAlertRequest struct {
-	BudgetID  string    `json:"budget_id" validate:"required"`
-	Type      AlertType `json:"type" validate:"required,oneof=percentage amount"`
-	Threshold float64   `json:"threshold" validate:"required,gt=0"`
+	BudgetID   string                `json:"budget_id" validate:"required"`
+	CategoryID string                `json:"category_id"`
+	Type       AlertType             `json:"type" validate:"required,oneof=percentage amount"`
+	Threshold  Money                 `json:"threshold" validate:"required_without=Tiers,omitempty,gt=0"`
+	Tiers      []Money               `json:"tiers" validate:"omitempty,max=10,dive,gt=0"`
+	Channels   []AlertChannelRequest `json:"channels" validate:"omitempty,dive"`
+}
+
+type AlertChannelRequest struct {
+	Type   NotificationChannel `json:"type" validate:"required,oneof=email webhook slack in_app"`
+	URL    string              `json:"url" validate:"omitempty,url,startswith=https://"`
+	Secret string              `json:"secret" validate:"omitempty,min=16"`
 }
 
+// AlertMessage describes a triggered alert to the notification channels. It
+// is also the JSON body posted to webhook channels.
+type AlertMessage struct {
+	Event           string              `json:"event"`
+	AlertID         primitive.ObjectID  `json:"alert_id"`
+	BudgetID        primitive.ObjectID  `json:"budget_id"`
+	BudgetName      string              `json:"budget_name"`
+	CategoryID      *primitive.ObjectID `json:"category_id,omitempty"`
+	CategoryName    string              `json:"category_name,omitempty"`
+	AlertType       AlertType           `json:"alert_type"`
+	Tier            Money               `json:"tier"`
+	Currency        Currency            `json:"currency"`
+	SpentAmount     Money               `json:"spent_amount"`
+	TotalAmount     Money               `json:"total_amount"`
+	PercentageSpent float64             `json:"percentage_spent"`
+	Text            string              `json:"text"`
+	TriggeredAt     time.Time           `json:"triggered_at"`
+}
+
+// AlertNotification records one attempt to deliver an alert on one channel.
+// Error holds the reason a failed attempt was not delivered. The in-app
+// notifications make up the user's inbox; ReadAt is set once the user has
+// read one.
 type AlertNotification struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	AlertID   primitive.ObjectID `bson:"alert_id" json:"alert_id"`
-	BudgetID  primitive.ObjectID `bson:"budget_id" json:"budget_id"`
-	Message   string             `bson:"message" json:"message"`
-	SentAt    time.Time          `bson:"sent_at" json:"sent_at"`
-	IsSuccess bool               `bson:"is_success" json:"is_success"`
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	AlertID   primitive.ObjectID  `bson:"alert_id" json:"alert_id"`
+	BudgetID  primitive.ObjectID  `bson:"budget_id" json:"budget_id"`
+	Channel   NotificationChannel `bson:"channel" json:"channel"`
+	Message   string              `bson:"message" json:"message"`
+	SentAt    time.Time           `bson:"sent_at" json:"sent_at"`
+	IsSuccess bool                `bson:"is_success" json:"is_success"`
+	Error     string              `bson:"error,omitempty" json:"error,omitempty"`
+	ReadAt    *time.Time          `bson:"read_at,omitempty" json:"read_at"`
+	Links     *NotificationLinks  `bson:"-" json:"links,omitempty"`
+}
+
+// NotificationLinks are the API paths of the budget and alert a notification
+// is about.
+type NotificationLinks struct {
+	Budget string `json:"budget"`
+	Alert  string `json:"alert"`
+}
+
+type NotificationQuery struct {
+	UserID     primitive.ObjectID
+	UnreadOnly bool
+	Limit      int
+	Cursor     string
+}
+
+type NotificationPage struct {
+	Items      []*AlertNotification `json:"items"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// MarkNotificationsRequest selects the notifications to mark read or unread:
+// the listed ones, or with All every notification in the inbox.
+type MarkNotificationsRequest struct {
+	IDs []string `json:"ids" validate:"required_without=All,max=200"`
+	All bool     `json:"all"`
 }
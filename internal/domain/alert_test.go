@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeAlertTiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		alertType AlertType
+		threshold Money
+		tiers     []Money
+		want      []Money
+		wantErr   bool
+	}{
+		{
+			name:      "lone threshold is a single tier",
+			alertType: AlertTypePercentage,
+			threshold: 8000,
+			want:      []Money{8000},
+		},
+		{
+			name:      "tiers are sorted",
+			alertType: AlertTypePercentage,
+			tiers:     []Money{10000, 5000, 8000},
+			want:      []Money{5000, 8000, 10000},
+		},
+		{
+			name:      "tiers win over threshold",
+			alertType: AlertTypeAmount,
+			threshold: 100,
+			tiers:     []Money{20000},
+			want:      []Money{20000},
+		},
+		{
+			name:      "percentage up to 1000%",
+			alertType: AlertTypePercentage,
+			tiers:     []Money{100000},
+			want:      []Money{100000},
+		},
+		{
+			name:      "percentage above 1000%",
+			alertType: AlertTypePercentage,
+			tiers:     []Money{100001},
+			wantErr:   true,
+		},
+		{
+			name:      "amounts above 1000 are fine",
+			alertType: AlertTypeAmount,
+			tiers:     []Money{500000},
+			want:      []Money{500000},
+		},
+		{
+			name:      "duplicate tiers",
+			alertType: AlertTypePercentage,
+			tiers:     []Money{8000, 5000, 8000},
+			wantErr:   true,
+		},
+		{
+			name:      "zero tier",
+			alertType: AlertTypeAmount,
+			tiers:     []Money{0, 100},
+			wantErr:   true,
+		},
+		{
+			name:      "negative tier",
+			alertType: AlertTypeAmount,
+			tiers:     []Money{-100},
+			wantErr:   true,
+		},
+		{
+			name:      "no threshold and no tiers",
+			alertType: AlertTypeAmount,
+			wantErr:   true,
+		},
+		{
+			name:      "too many tiers",
+			alertType: AlertTypeAmount,
+			tiers:     []Money{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(tt.tiers)
+			got, err := NormalizeAlertTiers(tt.alertType, tt.threshold, tt.tiers)
+			if tt.wantErr {
+				if err != ErrInvalidTiers {
+					t.Fatalf("err = %v, want %v", err, ErrInvalidTiers)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tiers = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(tt.tiers, input) {
+				t.Errorf("request tiers reordered to %v", tt.tiers)
+			}
+		})
+	}
+}
+
+func TestAlertReachedTiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		alert Alert
+		spent Money
+		total Money
+		want  []Money
+	}{
+		{
+			name:  "percentage below every tier",
+			alert: Alert{Type: AlertTypePercentage, Tiers: []Money{5000, 8000, 10000}},
+			spent: 4999,
+			total: 10000,
+		},
+		{
+			name:  "percentage exactly on a tier",
+			alert: Alert{Type: AlertTypePercentage, Tiers: []Money{5000, 8000, 10000}},
+			spent: 8000,
+			total: 10000,
+			want:  []Money{5000, 8000},
+		},
+		{
+			name:  "percentage over budget",
+			alert: Alert{Type: AlertTypePercentage, Tiers: []Money{5000, 8000, 10000}},
+			spent: 12000,
+			total: 10000,
+			want:  []Money{5000, 8000, 10000},
+		},
+		{
+			name:  "percentage with nothing budgeted",
+			alert: Alert{Type: AlertTypePercentage, Tiers: []Money{5000}},
+			spent: 100,
+		},
+		{
+			name:  "amount tiers",
+			alert: Alert{Type: AlertTypeAmount, Tiers: []Money{10000, 20000}},
+			spent: 15000,
+			total: 100,
+			want:  []Money{10000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.alert.ReachedTiers(tt.spent, tt.total)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("reached = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AnalyticsInterval string
+
+const (
+	AnalyticsIntervalDay   AnalyticsInterval = "day"
+	AnalyticsIntervalWeek  AnalyticsInterval = "week"
+	AnalyticsIntervalMonth AnalyticsInterval = "month"
+)
+
+// AnalyticsQuery scopes a report to one budget, or when BudgetID is nil to
+// BudgetIDs, which the service fills with every budget the user can see.
+// Timezone is the IANA zone used to bucket dates. With Rollup set, category
+// totals include the spend of their subcategories. Report rows are grouped by
+// currency as well, since expenses on budgets kept in different currencies
+// cannot be summed.
+type AnalyticsQuery struct {
+	UserID    primitive.ObjectID
+	BudgetID  *primitive.ObjectID
+	BudgetIDs []primitive.ObjectID
+	From      *time.Time
+	To        *time.Time
+	Interval  AnalyticsInterval
+	Timezone  string
+	Rollup    bool
+	Limit     int
+}
+
+type CategoryTotal struct {
+	CategoryID primitive.ObjectID  `bson:"category_id" json:"category_id"`
+	Category   string              `bson:"category" json:"category"`
+	ParentID   *primitive.ObjectID `bson:"-" json:"parent_id,omitempty"`
+	Currency   Currency            `bson:"currency" json:"currency"`
+	Total      Money               `bson:"total" json:"total"`
+	Count      int                 `bson:"count" json:"count"`
+}
+
+type SpendPoint struct {
+	Period   time.Time `bson:"period" json:"period"`
+	Currency Currency  `bson:"currency" json:"currency"`
+	Total    Money     `bson:"total" json:"total"`
+	Count    int       `bson:"count" json:"count"`
+}
+
+type DescriptionTotal struct {
+	Description string   `bson:"description" json:"description"`
+	Currency    Currency `bson:"currency" json:"currency"`
+	Total       Money    `bson:"total" json:"total"`
+	Count       int      `bson:"count" json:"count"`
+}
+
+type CategoryVsActual struct {
+	CategoryID primitive.ObjectID `json:"category_id"`
+	Name       string             `json:"name"`
+	Budgeted   Money              `json:"budgeted"`
+	Actual     Money              `json:"actual"`
+	Variance   Money              `json:"variance"`
+}
+
+// PeriodVsActual compares one period of a budget with what was spent in it.
+// Variance is budgeted minus actual, so overspending is negative.
+type PeriodVsActual struct {
+	BudgetID   primitive.ObjectID `json:"budget_id"`
+	StartDate  time.Time          `json:"start_date"`
+	EndDate    time.Time          `json:"end_date"`
+	Currency   Currency           `json:"currency"`
+	Budgeted   Money              `json:"budgeted"`
+	Actual     Money              `json:"actual"`
+	Variance   Money              `json:"variance"`
+	Categories []CategoryVsActual `json:"categories"`
+}
@@ -6,42 +6,146 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-type BudgetPeriod string
-
-const (
-	BudgetPeriodWeekly  BudgetPeriod = "weekly"
-	BudgetPeriodMonthly BudgetPeriod = "monthly"
-)
-
 type Budget struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	Name        string             `bson:"name" json:"name"`
-	Period      BudgetPeriod       `bson:"period" json:"period"`
-	StartDate   time.Time          `bson:"start_date" json:"start_date"`
-	EndDate     time.Time          `bson:"end_date" json:"end_date"`
-	Categories  []BudgetCategory   `bson:"categories" json:"categories"`
-	TotalAmount float64            `bson:"total_amount" json:"total_amount"`
-	SpentAmount float64            `bson:"spent_amount" json:"spent_amount"`
-	IsActive    bool               `bson:"is_active" json:"is_active"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID           primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Name             string              `bson:"name" json:"name"`
+	Period           BudgetPeriod        `bson:"period" json:"period"`
+	Mode             BudgetMode          `bson:"mode,omitempty" json:"mode,omitempty"`
+	Currency         Currency            `bson:"currency" json:"currency"`
+	StartDate        time.Time           `bson:"start_date" json:"start_date"`
+	EndDate          time.Time           `bson:"end_date" json:"end_date"`
+	PeriodAnchor     time.Time           `bson:"period_anchor,omitempty" json:"period_anchor"`
+	PeriodIndex      int                 `bson:"period_index" json:"period_index"`
+	Categories       []BudgetCategory    `bson:"categories" json:"categories"`
+	TotalAmount      Money               `bson:"total_amount" json:"total_amount"`
+	SpentAmount      Money               `bson:"spent_amount" json:"spent_amount"`
+	IncomeAmount     Money               `bson:"income_amount" json:"income_amount"`
+	IsActive         bool                `bson:"is_active" json:"is_active"`
+	CarryOver        bool                `bson:"carry_over" json:"carry_over"`
+	PreviousBudgetID *primitive.ObjectID `bson:"previous_budget_id,omitempty" json:"previous_budget_id,omitempty"`
+	NextBudgetID     *primitive.ObjectID `bson:"next_budget_id,omitempty" json:"next_budget_id,omitempty"`
+	TemplateID       *primitive.ObjectID `bson:"template_id,omitempty" json:"template_id,omitempty"`
+	Members          []BudgetMember      `bson:"members,omitempty" json:"members,omitempty"`
+	Version          int64               `bson:"version" json:"version"`
+	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time           `bson:"updated_at" json:"updated_at"`
 }
 
+// BudgetCategory tracks one category of the owner's catalog. Requests may
+// give Name instead of CategoryID; it is matched against the catalog and added
+// to it when missing.
+//
+// Amount is the spending limit for the period, or in envelope mode the money
+// assigned to the envelope. When a budget carries over, CarriedOver is the
+// part of Amount brought forward from the previous period, so
+// Amount - CarriedOver is the planned amount. An overspend is deducted from
+// the planned amount but never below zero; DroppedOverspend is the part of it
+// that did not fit and was not carried forward.
 type BudgetCategory struct {
-	Name        string  `bson:"name" json:"name"`
-	Amount      float64 `bson:"amount" json:"amount"`
-	SpentAmount float64 `bson:"spent_amount" json:"spent_amount"`
+	CategoryID       primitive.ObjectID `bson:"category_id" json:"category_id"`
+	Name             string             `bson:"name" json:"name"`
+	Amount           Money              `bson:"amount" json:"amount" validate:"gte=0"`
+	SpentAmount      Money              `bson:"spent_amount" json:"spent_amount"`
+	CarriedOver      Money              `bson:"carried_over" json:"carried_over"`
+	DroppedOverspend Money              `bson:"dropped_overspend,omitempty" json:"dropped_overspend,omitempty"`
+}
+
+// PeriodPosition returns the anchor of the budget's period series, the start
+// of its first budget, and the budget's index in the series. Budgets stored
+// before anchors were recorded start a series of their own.
+func (b *Budget) PeriodPosition() (time.Time, int) {
+	if b.PeriodAnchor.IsZero() {
+		return b.StartDate, 0
+	}
+	return b.PeriodAnchor, b.PeriodIndex
+}
+
+// NextPeriod returns the start and end of the period after the budget's,
+// counted from the series anchor so month-end periods do not drift. Custom
+// periods repeat with the same length.
+func (b *Budget) NextPeriod() (time.Time, time.Time) {
+	anchor, n := b.PeriodPosition()
+	if start, end, ok := b.Period.Bounds(anchor, n+1); ok {
+		return start, end
+	}
+	return b.EndDate, b.EndDate.Add(b.EndDate.Sub(b.StartDate))
+}
+
+// FindCategory returns the budget category for the given catalog category,
+// or nil if the budget does not track it.
+func (b *Budget) FindCategory(id primitive.ObjectID) *BudgetCategory {
+	for i := range b.Categories {
+		if b.Categories[i].CategoryID == id {
+			return &b.Categories[i]
+		}
+	}
+	return nil
+}
+
+// ResolveCategory returns the budget category identified by categoryID, a
+// catalog ID in hex, or when that is empty by name, compared the way catalog
+// names are.
+func (b *Budget) ResolveCategory(categoryID, name string) (*BudgetCategory, error) {
+	if categoryID != "" {
+		id, err := primitive.ObjectIDFromHex(categoryID)
+		if err != nil {
+			return nil, ErrInvalidInput
+		}
+		if category := b.FindCategory(id); category != nil {
+			return category, nil
+		}
+		return nil, ErrCategoryNotFound
+	}
+
+	key := CategoryKey(name)
+	for i := range b.Categories {
+		if CategoryKey(b.Categories[i].Name) == key {
+			return &b.Categories[i], nil
+		}
+	}
+	return nil, ErrCategoryNotFound
 }
 
 type CreateBudgetRequest struct {
 	Name       string           `json:"name" validate:"required"`
-	Period     BudgetPeriod     `json:"period" validate:"required,oneof=weekly monthly"`
+	Period     BudgetPeriod     `json:"period" validate:"required,budget_period"`
+	Mode       BudgetMode       `json:"mode" validate:"omitempty,oneof=limit envelope"`
+	Currency   Currency         `json:"currency" validate:"omitempty,iso4217"`
 	StartDate  time.Time        `json:"start_date" validate:"required"`
+	EndDate    *time.Time       `json:"end_date"`
+	CarryOver  bool             `json:"carry_over"`
 	Categories []BudgetCategory `json:"categories" validate:"required,min=1,dive"`
 }
 
 type UpdateBudgetRequest struct {
 	Name       string           `json:"name"`
-	Categories []BudgetCategory `json:"categories"`
+	CarryOver  *bool            `json:"carry_over"`
+	Categories []BudgetCategory `json:"categories" validate:"omitempty,dive"`
+}
+
+// BudgetDocument is the editable part of a budget. PATCH requests are applied
+// to it and the result replaces the budget's name, carry-over setting and
+// categories, so a field can be cleared or a single category changed. Spent
+// and carried-over amounts in the document are ignored.
+type BudgetDocument struct {
+	Name       string           `json:"name" validate:"required"`
+	CarryOver  bool             `json:"carry_over"`
+	Categories []BudgetCategory `json:"categories" validate:"required,min=1,dive"`
+}
+
+func (b *Budget) Document() *BudgetDocument {
+	return &BudgetDocument{
+		Name:       b.Name,
+		CarryOver:  b.CarryOver,
+		Categories: b.Categories,
+	}
+}
+
+func (d *BudgetDocument) UpdateRequest() *UpdateBudgetRequest {
+	return &UpdateBudgetRequest{
+		Name:       d.Name,
+		CarryOver:  &d.CarryOver,
+		Categories: d.Categories,
+	}
 }
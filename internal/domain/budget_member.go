@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MemberRole string
+
+const (
+	MemberRoleOwner  MemberRole = "owner"
+	MemberRoleEditor MemberRole = "editor"
+	MemberRoleViewer MemberRole = "viewer"
+)
+
+// rank orders roles so that a higher role includes every permission of the
+// roles below it.
+func (r MemberRole) rank() int {
+	switch r {
+	case MemberRoleOwner:
+		return 3
+	case MemberRoleEditor:
+		return 2
+	case MemberRoleViewer:
+		return 1
+	}
+	return 0
+}
+
+// BudgetMember is a user the budget is shared with. The owner is the budget's
+// UserID and is not listed among the members.
+type BudgetMember struct {
+	UserID   primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Role     MemberRole         `bson:"role" json:"role"`
+	JoinedAt time.Time          `bson:"joined_at" json:"joined_at"`
+}
+
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "pending"
+	InvitationStatusAccepted InvitationStatus = "accepted"
+	InvitationStatusRevoked  InvitationStatus = "revoked"
+)
+
+// BudgetInvitation emails are stored in lower case, since addresses are
+// matched against the invitee's account regardless of case.
+type BudgetInvitation struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	BudgetID   primitive.ObjectID  `bson:"budget_id" json:"budget_id"`
+	BudgetName string              `bson:"budget_name" json:"budget_name"`
+	InvitedBy  primitive.ObjectID  `bson:"invited_by" json:"invited_by"`
+	Email      string              `bson:"email" json:"email"`
+	Role       MemberRole          `bson:"role" json:"role"`
+	Token      string              `bson:"token" json:"-"`
+	Status     InvitationStatus    `bson:"status" json:"status"`
+	ExpiresAt  time.Time           `bson:"expires_at" json:"expires_at"`
+	AcceptedBy *primitive.ObjectID `bson:"accepted_by,omitempty" json:"accepted_by,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+type InviteMemberRequest struct {
+	Email string     `json:"email" validate:"required,email"`
+	Role  MemberRole `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type UpdateMemberRequest struct {
+	Role MemberRole `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+// RoleOf returns the user's role on the budget, or an empty role when the
+// budget is not shared with them.
+func (b *Budget) RoleOf(userID primitive.ObjectID) MemberRole {
+	if b.UserID == userID {
+		return MemberRoleOwner
+	}
+	for _, member := range b.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// Authorize returns ErrUnauthorized unless the user holds at least the given
+// role on the budget.
+func (b *Budget) Authorize(userID primitive.ObjectID, role MemberRole) error {
+	if b.RoleOf(userID).rank() < role.rank() {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// MemberIDs returns the owner followed by every member of the budget.
+func (b *Budget) MemberIDs() []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(b.Members)+1)
+	ids = append(ids, b.UserID)
+	for _, member := range b.Members {
+		ids = append(ids, member.UserID)
+	}
+	return ids
+}
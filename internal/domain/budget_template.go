@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BudgetTemplate is a reusable budget layout. Budgets created from a template
+// keep a reference to it, and rollover builds their next period from the
+// template's current categories.
+type BudgetTemplate struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name       string             `bson:"name" json:"name"`
+	Period     BudgetPeriod       `bson:"period" json:"period"`
+	Currency   Currency           `bson:"currency,omitempty" json:"currency,omitempty"`
+	CarryOver  bool               `bson:"carry_over" json:"carry_over"`
+	Categories []TemplateCategory `bson:"categories" json:"categories"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// TemplateCategory refers to the catalog like BudgetCategory does.
+type TemplateCategory struct {
+	CategoryID primitive.ObjectID `bson:"category_id" json:"category_id"`
+	Name       string             `bson:"name" json:"name"`
+	Amount     Money              `bson:"amount" json:"amount" validate:"gte=0"`
+}
+
+// BudgetCategories returns the template categories with nothing spent.
+func (t *BudgetTemplate) BudgetCategories() []BudgetCategory {
+	categories := make([]BudgetCategory, len(t.Categories))
+	for i, category := range t.Categories {
+		categories[i] = BudgetCategory{
+			CategoryID: category.CategoryID,
+			Name:       category.Name,
+			Amount:     category.Amount,
+		}
+	}
+	return categories
+}
+
+// Custom periods have no fixed length, so templates cannot use them.
+type CreateBudgetTemplateRequest struct {
+	Name       string             `json:"name" validate:"required"`
+	Period     BudgetPeriod       `json:"period" validate:"required,budget_period,ne=custom"`
+	Currency   Currency           `json:"currency" validate:"omitempty,iso4217"`
+	CarryOver  bool               `json:"carry_over"`
+	Categories []TemplateCategory `json:"categories" validate:"required,min=1,dive"`
+}
+
+// NewBudgetRequest starts a budget from a template or an existing budget. Name
+// defaults to the source's name, and EndDate is only used for custom periods.
+type NewBudgetRequest struct {
+	Name      string     `json:"name"`
+	StartDate time.Time  `json:"start_date" validate:"required"`
+	EndDate   *time.Time `json:"end_date"`
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category is an entry in a user's category catalog. Budgets, templates,
+// expenses and recurring expenses refer to categories by ID and keep a copy of
+// the name for display, which is updated when the category is renamed. Key is
+// the normalized name; it is unique per user so "Food" and "food" cannot
+// become two categories.
+type Category struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	ParentID   *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	Name       string              `bson:"name" json:"name"`
+	Key        string              `bson:"key" json:"-"`
+	Color      string              `bson:"color,omitempty" json:"color,omitempty"`
+	Icon       string              `bson:"icon,omitempty" json:"icon,omitempty"`
+	IsArchived bool                `bson:"is_archived" json:"is_archived"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// CategoryKey normalizes a category name for lookups and uniqueness checks.
+func CategoryKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+type CreateCategoryRequest struct {
+	Name     string `json:"name" validate:"required,max=64"`
+	ParentID string `json:"parent_id"`
+	Color    string `json:"color" validate:"omitempty,hexcolor"`
+	Icon     string `json:"icon" validate:"omitempty,max=64"`
+}
+
+// UpdateCategoryRequest changes only the fields that are set. An empty
+// ParentID moves the category to the top level. Archiving or restoring a
+// category applies to its subcategories as well.
+type UpdateCategoryRequest struct {
+	Name       string  `json:"name" validate:"omitempty,max=64"`
+	ParentID   *string `json:"parent_id"`
+	Color      *string `json:"color" validate:"omitempty,hexcolor"`
+	Icon       *string `json:"icon" validate:"omitempty,max=64"`
+	IsArchived *bool   `json:"is_archived"`
+}
+
+// CategoryTree indexes a user's catalog by ID and by parent.
+type CategoryTree struct {
+	byID     map[primitive.ObjectID]*Category
+	children map[primitive.ObjectID][]*Category
+}
+
+func NewCategoryTree(categories []*Category) *CategoryTree {
+	tree := &CategoryTree{
+		byID:     make(map[primitive.ObjectID]*Category, len(categories)),
+		children: make(map[primitive.ObjectID][]*Category),
+	}
+	for _, category := range categories {
+		tree.byID[category.ID] = category
+		if category.ParentID != nil {
+			tree.children[*category.ParentID] = append(tree.children[*category.ParentID], category)
+		}
+	}
+	return tree
+}
+
+// Find returns the category with the given ID, or nil if the tree does not
+// hold it.
+func (t *CategoryTree) Find(id primitive.ObjectID) *Category {
+	return t.byID[id]
+}
+
+// Ancestors returns the parent, grandparent and so on of the category, nearest
+// first. Parents missing from the tree end the chain.
+func (t *CategoryTree) Ancestors(id primitive.ObjectID) []*Category {
+	var ancestors []*Category
+	category := t.byID[id]
+	for category != nil && category.ParentID != nil && len(ancestors) < len(t.byID) {
+		category = t.byID[*category.ParentID]
+		if category == nil {
+			break
+		}
+		ancestors = append(ancestors, category)
+	}
+	return ancestors
+}
+
+// Descendants returns every category below the given one, depth first.
+func (t *CategoryTree) Descendants(id primitive.ObjectID) []*Category {
+	var descendants []*Category
+	for _, child := range t.children[id] {
+		descendants = append(descendants, child)
+		descendants = append(descendants, t.Descendants(child.ID)...)
+	}
+	return descendants
+}
+
+// IsDescendant reports whether id sits anywhere below ancestorID.
+func (t *CategoryTree) IsDescendant(id, ancestorID primitive.ObjectID) bool {
+	for _, ancestor := range t.Ancestors(id) {
+		if ancestor.ID == ancestorID {
+			return true
+		}
+	}
+	return false
+}
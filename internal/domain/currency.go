@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Currency is an upper-case ISO 4217 currency code such as "EUR".
+type Currency string
+
+// ExchangeRate is the number of Quote units one Base unit buys on Date.
+type ExchangeRate struct {
+	ID        primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Base      Currency             `bson:"base" json:"base"`
+	Quote     Currency             `bson:"quote" json:"quote"`
+	Rate      primitive.Decimal128 `bson:"rate" json:"rate"`
+	Date      time.Time            `bson:"date" json:"date"`
+	Source    string               `bson:"source" json:"source"`
+	UpdatedAt time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// RatFromDecimal converts a stored decimal128 rate to an exact rational.
+func RatFromDecimal(d primitive.Decimal128) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		return nil, ErrInvalidInput
+	}
+	return r, nil
+}
+
+// DecimalFromRat stores r as a decimal128 rounded to ten fractional digits,
+// which is enough for any cross rate derived from published reference rates.
+func DecimalFromRat(r *big.Rat) (primitive.Decimal128, error) {
+	return primitive.ParseDecimal128(r.FloatString(10))
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BudgetMode string
+
+const (
+	// BudgetModeLimit caps spending per category. It is the default, and
+	// budgets stored before modes existed have an empty mode.
+	BudgetModeLimit BudgetMode = "limit"
+	// BudgetModeEnvelope is zero-based budgeting: income is recorded on the
+	// budget and assigned to categories, whose Amount is the money assigned.
+	BudgetModeEnvelope BudgetMode = "envelope"
+)
+
+type TransferType string
+
+const (
+	TransferTypeIncome   TransferType = "income"
+	TransferTypeAssign   TransferType = "assign"
+	TransferTypeUnassign TransferType = "unassign"
+	TransferTypeMove     TransferType = "move"
+)
+
+// EnvelopeTransfer records one movement of money in an envelope budget.
+// From and To are the names of the categories involved, as they were at the
+// time; an empty one is the budget's pool of income still to be assigned.
+type EnvelopeTransfer struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	BudgetID  primitive.ObjectID  `bson:"budget_id" json:"budget_id"`
+	UserID    primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Type      TransferType        `bson:"type" json:"type"`
+	FromID    *primitive.ObjectID `bson:"from_category_id,omitempty" json:"from_category_id,omitempty"`
+	From      string              `bson:"from,omitempty" json:"from,omitempty"`
+	ToID      *primitive.ObjectID `bson:"to_category_id,omitempty" json:"to_category_id,omitempty"`
+	To        string              `bson:"to,omitempty" json:"to,omitempty"`
+	Amount    Money               `bson:"amount" json:"amount"`
+	Note      string              `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+}
+
+type Envelope struct {
+	CategoryID primitive.ObjectID `json:"category_id"`
+	Name       string             `json:"name"`
+	Assigned   Money              `json:"assigned"`
+	Spent      Money              `json:"spent"`
+	Available  Money              `json:"available"`
+}
+
+type EnvelopeSummary struct {
+	BudgetID     primitive.ObjectID `json:"budget_id"`
+	Income       Money              `json:"income"`
+	Assigned     Money              `json:"assigned"`
+	ToBeAssigned Money              `json:"to_be_assigned"`
+	Envelopes    []Envelope         `json:"envelopes"`
+}
+
+type RecordIncomeRequest struct {
+	Amount Money  `json:"amount" validate:"required,gt=0"`
+	Note   string `json:"note"`
+}
+
+// EnvelopeTransferRequest moves money between envelopes, given by category
+// ID. Leaving From empty assigns money from the to-be-assigned pool; leaving
+// To empty returns it.
+type EnvelopeTransferRequest struct {
+	From   string `json:"from" validate:"required_without=To,nefield=To"`
+	To     string `json:"to"`
+	Amount Money  `json:"amount" validate:"required,gt=0"`
+	Note   string `json:"note"`
+}
+
+func (b *Budget) IsEnvelope() bool {
+	return b.Mode == BudgetModeEnvelope
+}
+
+// ToBeAssigned returns the income not yet assigned to an envelope.
+func (b *Budget) ToBeAssigned() Money {
+	return b.IncomeAmount - b.TotalAmount
+}
+
+// Available returns what is left to spend in the category. It is negative
+// when the category is overspent.
+func (c *BudgetCategory) Available() Money {
+	return c.Amount - c.SpentAmount
+}
+
+func (b *Budget) EnvelopeSummary() *EnvelopeSummary {
+	summary := &EnvelopeSummary{
+		BudgetID:     b.ID,
+		Income:       b.IncomeAmount,
+		Assigned:     b.TotalAmount,
+		ToBeAssigned: b.ToBeAssigned(),
+		Envelopes:    make([]Envelope, len(b.Categories)),
+	}
+	for i := range b.Categories {
+		category := &b.Categories[i]
+		summary.Envelopes[i] = Envelope{
+			CategoryID: category.CategoryID,
+			Name:       category.Name,
+			Assigned:   category.Amount,
+			Spent:      category.SpentAmount,
+			Available:  category.Available(),
+		}
+	}
+	return summary
+}
@@ -3,18 +3,41 @@ package domain
 import "errors"
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidToken       = errors.New("invalid token")
-	ErrTokenExpired       = errors.New("token expired")
-	ErrBudgetNotFound     = errors.New("budget not found")
-	ErrExpenseNotFound    = errors.New("expense not found")
-	ErrAlertNotFound      = errors.New("alert not found")
-	ErrUnauthorized       = errors.New("unauthorized access")
-	ErrInvalidInput       = errors.New("invalid input")
-	ErrInternalServer     = errors.New("internal server error")
-	ErrRateLimitExceeded  = errors.New("rate limit exceeded")
-	ErrDatabaseError      = errors.New("database error")
-	ErrInvalidObjectID    = errors.New("invalid objectID")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUserAlreadyExists    = errors.New("user already exists")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrTokenExpired         = errors.New("token expired")
+	ErrTokenReused          = errors.New("refresh token reuse detected")
+	ErrBudgetNotFound       = errors.New("budget not found")
+	ErrBudgetRolledOver     = errors.New("budget has already been rolled over")
+	ErrInvalidPeriod        = errors.New("invalid budget period")
+	ErrCategoryNotFound     = errors.New("category not found")
+	ErrCategoryInUse        = errors.New("category has recorded expenses")
+	ErrCategoryExists       = errors.New("category already exists")
+	ErrCategoryArchived     = errors.New("category is archived")
+	ErrInvalidParent        = errors.New("category cannot be moved under itself or a subcategory")
+	ErrTemplateNotFound     = errors.New("budget template not found")
+	ErrNotEnvelopeBudget    = errors.New("budget is not in envelope mode")
+	ErrInsufficientFunds    = errors.New("insufficient funds")
+	ErrMemberNotFound       = errors.New("budget member not found")
+	ErrAlreadyMember        = errors.New("user is already a member of the budget")
+	ErrInvitationNotFound   = errors.New("invitation not found or expired")
+	ErrExpenseNotFound      = errors.New("expense not found")
+	ErrRecurringNotFound    = errors.New("recurring expense not found")
+	ErrOccurrenceExists     = errors.New("recurring occurrence already recorded")
+	ErrAlertNotFound        = errors.New("alert not found")
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrInvalidTiers         = errors.New("alert tiers must be distinct positive thresholds")
+	ErrGoalNotFound         = errors.New("goal not found")
+	ErrContributionNotFound = errors.New("contribution not found")
+	ErrUnauthorized         = errors.New("unauthorized access")
+	ErrInvalidInput         = errors.New("invalid input")
+	ErrInternalServer       = errors.New("internal server error")
+	ErrRateLimitExceeded    = errors.New("rate limit exceeded")
+	ErrDatabaseError        = errors.New("database error")
+	ErrInvalidObjectID      = errors.New("invalid objectID")
+	ErrInvalidCursor        = errors.New("invalid cursor")
+	ErrRateNotFound         = errors.New("exchange rate not found")
+	ErrVersionConflict      = errors.New("resource has been modified by another request")
 )
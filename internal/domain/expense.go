@@ -6,22 +6,88 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Expense amounts are kept in the budget currency so they can be summed
+// against the budget. OriginalAmount and OriginalCurrency record what was
+// actually paid, and ExchangeRate the rate used to convert it. UserID is the
+// owner of the budget and CreatedBy the member who recorded the expense.
+// CategoryID is the budget category charged and Category its name.
 type Expense struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	BudgetID    primitive.ObjectID `bson:"budget_id" json:"budget_id"`
-	Category    string             `bson:"category" json:"category"`
-	Amount      float64            `bson:"amount" json:"amount"`
-	Description string             `bson:"description" json:"description"`
-	Date        time.Time          `bson:"date" json:"date"`
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID                 primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	UserID             primitive.ObjectID   `bson:"user_id" json:"user_id"`
+	CreatedBy          primitive.ObjectID   `bson:"created_by" json:"created_by"`
+	BudgetID           primitive.ObjectID   `bson:"budget_id" json:"budget_id"`
+	CategoryID         primitive.ObjectID   `bson:"category_id" json:"category_id"`
+	Category           string               `bson:"category" json:"category"`
+	Amount             Money                `bson:"amount" json:"amount"`
+	Currency           Currency             `bson:"currency" json:"currency"`
+	OriginalAmount     Money                `bson:"original_amount" json:"original_amount"`
+	OriginalCurrency   Currency             `bson:"original_currency" json:"original_currency"`
+	ExchangeRate       primitive.Decimal128 `bson:"exchange_rate" json:"exchange_rate"`
+	Description        string               `bson:"description" json:"description"`
+	Date               time.Time            `bson:"date" json:"date"`
+	RecurringExpenseID *primitive.ObjectID  `bson:"recurring_expense_id,omitempty" json:"recurring_expense_id,omitempty"`
+	OccurrenceDate     *time.Time           `bson:"occurrence_date,omitempty" json:"occurrence_date,omitempty"`
+	Version            int64                `bson:"version" json:"version"`
+	CreatedAt          time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time            `bson:"updated_at" json:"updated_at"`
 }
 
+// CreateExpenseRequest names the budget category by CategoryID, or by
+// Category for clients that only know its name.
 type CreateExpenseRequest struct {
 	BudgetID    string    `json:"budget_id" validate:"required"`
-	Category    string    `json:"category" validate:"required"`
-	Amount      float64   `json:"amount" validate:"required,gt=0"`
+	CategoryID  string    `json:"category_id"`
+	Category    string    `json:"category" validate:"required_without=CategoryID"`
+	Amount      Money     `json:"amount" validate:"required,gt=0"`
+	Currency    Currency  `json:"currency" validate:"omitempty,iso4217"`
 	Description string    `json:"description"`
 	Date        time.Time `json:"date"  validate:"required"`
 }
+
+// Document returns the request that describes the expense as it is. PATCH
+// requests are applied to it and the result replaces the expense. A patch
+// moves the expense to another category by changing category_id, or by
+// removing it and setting category.
+func (e *Expense) Document() *CreateExpenseRequest {
+	return &CreateExpenseRequest{
+		BudgetID:    e.BudgetID.Hex(),
+		CategoryID:  e.CategoryID.Hex(),
+		Category:    e.Category,
+		Amount:      e.OriginalAmount,
+		Currency:    e.OriginalCurrency,
+		Description: e.Description,
+		Date:        e.Date,
+	}
+}
+
+type ExpenseSortField string
+
+const (
+	ExpenseSortDate   ExpenseSortField = "date"
+	ExpenseSortAmount ExpenseSortField = "amount"
+)
+
+// ExpenseQuery describes a filtered, sorted page of expenses. Either BudgetID
+// or BudgetIDs scopes the query, the latter holding every budget the user
+// can see; optional filters are left nil or empty. CategoryID filters on a
+// category and its subcategories, which the service expands into Categories.
+type ExpenseQuery struct {
+	BudgetID   *primitive.ObjectID
+	BudgetIDs  []primitive.ObjectID
+	From       *time.Time
+	To         *time.Time
+	CategoryID *primitive.ObjectID
+	Categories []primitive.ObjectID
+	MinAmount  *Money
+	MaxAmount  *Money
+	Search     string
+	SortBy     ExpenseSortField
+	SortDesc   bool
+	Limit      int
+	Cursor     string
+}
+
+type ExpensePage struct {
+	Items      []*Expense `json:"items"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
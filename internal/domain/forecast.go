@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ForecastLine projects spending to the end of a budget period. Low and High
+// bound the projection. OverspendDate is the day spending is expected to pass
+// Limit, the current day if it already has, or nil if it is not expected to.
+type ForecastLine struct {
+	Limit             Money      `json:"limit"`
+	Spent             Money      `json:"spent"`
+	UpcomingRecurring Money      `json:"upcoming_recurring"`
+	Projected         Money      `json:"projected"`
+	Low               Money      `json:"low"`
+	High              Money      `json:"high"`
+	OverspendDate     *time.Time `json:"overspend_date,omitempty"`
+}
+
+type CategoryForecast struct {
+	CategoryID primitive.ObjectID `json:"category_id"`
+	Name       string             `json:"name"`
+	ForecastLine
+}
+
+type BudgetForecast struct {
+	BudgetID       primitive.ObjectID `json:"budget_id"`
+	Currency       Currency           `json:"currency"`
+	AsOf           time.Time          `json:"as_of"`
+	StartDate      time.Time          `json:"start_date"`
+	EndDate        time.Time          `json:"end_date"`
+	DaysElapsed    int                `json:"days_elapsed"`
+	DaysTotal      int                `json:"days_total"`
+	HistoryPeriods int                `json:"history_periods"`
+	Total          ForecastLine       `json:"total"`
+	Categories     []CategoryForecast `json:"categories"`
+}
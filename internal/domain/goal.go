@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// averageMonthDays is the mean length of a Gregorian month, used to spread
+// what is left of a goal over the months remaining.
+const averageMonthDays = 365.2425 / 12
+
+// Goal is a savings target. SavedAmount is the sum of its contributions.
+// CategoryID optionally refers to the catalog category the savings are set
+// aside under, and Category is its name.
+type Goal struct {
+	ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID           primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Name             string              `bson:"name" json:"name"`
+	CategoryID       *primitive.ObjectID `bson:"category_id,omitempty" json:"category_id,omitempty"`
+	Category         string              `bson:"category,omitempty" json:"category,omitempty"`
+	Currency         Currency            `bson:"currency" json:"currency"`
+	TargetAmount     Money               `bson:"target_amount" json:"target_amount"`
+	SavedAmount      Money               `bson:"saved_amount" json:"saved_amount"`
+	StartDate        time.Time           `bson:"start_date" json:"start_date"`
+	TargetDate       time.Time           `bson:"target_date" json:"target_date"`
+	BehindNotifiedAt *time.Time          `bson:"behind_notified_at,omitempty" json:"-"`
+	Progress         *GoalProgress       `bson:"-" json:"progress,omitempty"`
+	CreatedAt        time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// GoalProgress is computed from a goal's contributions and schedule.
+// ExpectedAmount is what would have been saved by now had the goal been
+// funded evenly from its start date, and RequiredMonthly what is still needed
+// each month to reach the target on time.
+type GoalProgress struct {
+	Percent         float64 `json:"percent"`
+	Remaining       Money   `json:"remaining"`
+	ExpectedAmount  Money   `json:"expected_amount"`
+	RequiredMonthly Money   `json:"required_monthly"`
+	MonthsLeft      float64 `json:"months_left"`
+	IsCompleted     bool    `json:"is_completed"`
+	IsBehind        bool    `json:"is_behind"`
+}
+
+func (g *Goal) ComputeProgress(now time.Time) GoalProgress {
+	progress := GoalProgress{
+		Percent:     g.SavedAmount.Percent(g.TargetAmount),
+		IsCompleted: g.SavedAmount >= g.TargetAmount,
+	}
+	if progress.IsCompleted {
+		progress.ExpectedAmount = g.TargetAmount
+		return progress
+	}
+	progress.Remaining = g.TargetAmount - g.SavedAmount
+
+	elapsed := 1.0
+	if total := g.TargetDate.Sub(g.StartDate); total > 0 {
+		elapsed = math.Max(0, math.Min(1, float64(now.Sub(g.StartDate))/float64(total)))
+	}
+	progress.ExpectedAmount = Money(math.Round(float64(g.TargetAmount) * elapsed))
+	progress.IsBehind = g.SavedAmount < progress.ExpectedAmount
+
+	progress.RequiredMonthly = progress.Remaining
+	if now.Before(g.TargetDate) {
+		progress.MonthsLeft = g.TargetDate.Sub(now).Hours() / 24 / averageMonthDays
+		if progress.MonthsLeft > 1 {
+			progress.RequiredMonthly = Money(math.Ceil(float64(progress.Remaining) / progress.MonthsLeft))
+		}
+	}
+
+	return progress
+}
+
+type GoalContribution struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	GoalID    primitive.ObjectID `bson:"goal_id" json:"goal_id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Amount    Money              `bson:"amount" json:"amount"`
+	Note      string             `bson:"note,omitempty" json:"note,omitempty"`
+	Date      time.Time          `bson:"date" json:"date"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// CreateGoalRequest may name a catalog category by CategoryID, or by
+// Category, which is added to the catalog when missing.
+type CreateGoalRequest struct {
+	Name         string     `json:"name" validate:"required"`
+	CategoryID   string     `json:"category_id"`
+	Category     string     `json:"category"`
+	Currency     Currency   `json:"currency" validate:"omitempty,iso4217"`
+	TargetAmount Money      `json:"target_amount" validate:"required,gt=0"`
+	StartDate    *time.Time `json:"start_date"`
+	TargetDate   time.Time  `json:"target_date" validate:"required"`
+}
+
+type UpdateGoalRequest struct {
+	Name         string    `json:"name" validate:"required"`
+	CategoryID   string    `json:"category_id"`
+	Category     string    `json:"category"`
+	TargetAmount Money     `json:"target_amount" validate:"required,gt=0"`
+	TargetDate   time.Time `json:"target_date" validate:"required"`
+}
+
+type CreateContributionRequest struct {
+	Amount Money      `json:"amount" validate:"required,gt=0"`
+	Note   string     `json:"note"`
+	Date   *time.Time `json:"date"`
+}
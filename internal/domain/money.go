@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// MoneyScale is the number of minor units in one major unit.
+const MoneyScale = 100
+
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// Money is an exact monetary amount held as an integer number of minor units
+// (hundredths), so sums and increments never accumulate rounding error. It is
+// stored in MongoDB as an int64 and encoded in JSON as a decimal number with
+// two fractional digits, e.g. 12.34.
+type Money int64
+
+// ParseMoney parses a decimal string such as "12.34" or "-5". Amounts with
+// more precision than the minor unit are rejected rather than rounded.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidMoney
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, ErrInvalidMoney
+	}
+
+	return moneyFromRat(r)
+}
+
+func moneyFromRat(r *big.Rat) (Money, error) {
+	minor := new(big.Rat).Mul(r, big.NewRat(MoneyScale, 1))
+	if !minor.IsInt() || !minor.Num().IsInt64() {
+		return 0, ErrInvalidMoney
+	}
+
+	return Money(minor.Num().Int64()), nil
+}
+
+// MoneyFromFloat converts a float64 amount, rounding to the nearest minor
+// unit. It exists for legacy data and must not be used for new arithmetic.
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * MoneyScale))
+}
+
+func (m Money) String() string {
+	sign := ""
+	minor := int64(m)
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/MoneyScale, minor%MoneyScale)
+}
+
+// Float64 returns the amount as a float for display and ratio calculations.
+func (m Money) Float64() float64 {
+	return float64(m) / MoneyScale
+}
+
+// Percent returns m as a percentage of total, or 0 when total is zero.
+func (m Money) Percent(total Money) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(m) / float64(total) * 100
+}
+
+// Convert multiplies m by an exchange rate and rounds half away from zero to
+// the nearest minor unit.
+func (m Money) Convert(rate *big.Rat) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(m)), rate)
+
+	quotient, remainder := new(big.Int).QuoRem(product.Num(), product.Denom(), new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(product.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrInvalidMoney
+	}
+	return Money(quotient.Int64()), nil
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string. The literal text
+// is parsed directly so values never pass through float64.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bsontype.Int64, bsoncore.AppendInt64(nil, int64(m)), nil
+}
+
+// UnmarshalBSONValue reads the int64 minor units written by MarshalBSONValue.
+// Doubles (documents written before amounts were stored as minor units) and
+// decimal128 values are converted so unmigrated documents stay readable.
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bsoncore.Value{Type: t, Data: data}
+
+	switch t {
+	case bsontype.Int64:
+		if v, ok := value.Int64OK(); ok {
+			*m = Money(v)
+			return nil
+		}
+	case bsontype.Int32:
+		if v, ok := value.Int32OK(); ok {
+			*m = Money(v)
+			return nil
+		}
+	case bsontype.Double:
+		if v, ok := value.DoubleOK(); ok {
+			*m = MoneyFromFloat(v)
+			return nil
+		}
+	case bsontype.Decimal128:
+		if v, ok := value.Decimal128OK(); ok {
+			parsed, err := ParseMoney(v.String())
+			if err != nil {
+				return err
+			}
+			*m = parsed
+			return nil
+		}
+	case bsontype.Null:
+		*m = 0
+		return nil
+	}
+
+	return fmt.Errorf("%w: cannot decode BSON %s", ErrInvalidMoney, t)
+}
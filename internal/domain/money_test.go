@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: "12.34", want: 1234},
+		{input: "-5", want: -500},
+		{input: " 7.5 ", want: 750},
+		{input: "0.01", want: 1},
+		{input: "0.10", want: 10},
+		{input: "1e2", want: 10000},
+		{input: "1.230", want: 123},
+		{input: "1.234", wantErr: true},
+		{input: "0.001", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "1e30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Fatalf("ParseMoney(%q) err = %v, want %v", tt.input, err, ErrInvalidMoney)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseMoney(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: 0, want: "0.00"},
+		{money: 1234, want: "12.34"},
+		{money: 5, want: "0.05"},
+		{money: -5, want: "-0.05"},
+		{money: -1200, want: "-12.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  Money
+	}{
+		{input: 0.1 + 0.2, want: 30},
+		{input: 19.99, want: 1999},
+		{input: 1.005, want: 100},
+		{input: -19.99, want: -1999},
+	}
+
+	for _, tt := range tests {
+		if got := MoneyFromFloat(tt.input); got != tt.want {
+			t.Errorf("MoneyFromFloat(%v) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: `12.34`, want: 1234},
+		{input: `"12.34"`, want: 1234},
+		{input: `0.3`, want: 30},
+		{input: `null`, want: 0},
+		{input: `12.345`, wantErr: true},
+		{input: `"twelve"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Unmarshal(%s) = %d, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		money   Money
+		rate    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "exact", money: 1000, rate: "1.5", want: 1500},
+		{name: "rounds down below half", money: 1, rate: "0.49", want: 0},
+		{name: "rounds half up", money: 1, rate: "0.5", want: 1},
+		{name: "rounds half away from zero", money: -1, rate: "0.5", want: -1},
+		{name: "rounds half away from zero at scale", money: 100, rate: "1.005", want: 101},
+		{name: "negative rounds half away", money: -100, rate: "1.005", want: -101},
+		{name: "repeating fraction", money: 1000, rate: "1/3", want: 333},
+		{name: "many rate digits", money: 123456, rate: "0.8547008547", want: 105518},
+		{name: "zero", money: 0, rate: "1.2345", want: 0},
+		{name: "overflow", money: math.MaxInt64, rate: "2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := new(big.Rat).SetString(tt.rate)
+			if !ok {
+				t.Fatalf("bad rate %q", tt.rate)
+			}
+
+			got, err := tt.money.Convert(rate)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Fatalf("Convert err = %v, want %v", err, ErrInvalidMoney)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Money(%d).Convert(%s) = %d, want %d", tt.money, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EventType string
+
+const (
+	EventExpenseCreated EventType = "expense.created"
+	EventExpenseUpdated EventType = "expense.updated"
+	EventExpenseDeleted EventType = "expense.deleted"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// it describes, so it is published exactly when the change commits. The
+// worker claims pending events by setting LockedUntil, and marks them
+// processed once handled. An event whose handler fails is retried after its
+// lock expires; once it has used up its attempts it is marked dead with
+// DeadAt and left for inspection until cleanup removes it.
+type OutboxEvent struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type        EventType          `bson:"type" json:"type"`
+	BudgetID    primitive.ObjectID `bson:"budget_id" json:"budget_id"`
+	ExpenseID   primitive.ObjectID `bson:"expense_id" json:"expense_id"`
+	OccurredAt  time.Time          `bson:"occurred_at" json:"occurred_at"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	LockedUntil *time.Time         `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	ProcessedAt *time.Time         `bson:"processed_at,omitempty" json:"processed_at,omitempty"`
+	DeadAt      *time.Time         `bson:"dead_at,omitempty" json:"dead_at,omitempty"`
+	LastError   string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+}
+
+func NewExpenseEvent(eventType EventType, expense *Expense) *OutboxEvent {
+	return &OutboxEvent{
+		Type:      eventType,
+		BudgetID:  expense.BudgetID,
+		ExpenseID: expense.ID,
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+type BudgetPeriod string
+
+const (
+	BudgetPeriodDaily     BudgetPeriod = "daily"
+	BudgetPeriodWeekly    BudgetPeriod = "weekly"
+	BudgetPeriodBiweekly  BudgetPeriod = "biweekly"
+	BudgetPeriodMonthly   BudgetPeriod = "monthly"
+	BudgetPeriodQuarterly BudgetPeriod = "quarterly"
+	BudgetPeriodYearly    BudgetPeriod = "yearly"
+	BudgetPeriodCustom    BudgetPeriod = "custom"
+)
+
+func (p BudgetPeriod) IsValid() bool {
+	switch p {
+	case BudgetPeriodDaily, BudgetPeriodWeekly, BudgetPeriodBiweekly, BudgetPeriodMonthly,
+		BudgetPeriodQuarterly, BudgetPeriodYearly, BudgetPeriodCustom:
+		return true
+	}
+	return false
+}
+
+// Bounds returns the start and end of the nth (zero-based) period of a
+// series whose first period began at anchor. Every period is measured from
+// the anchor rather than from the period before it, so month-based periods
+// clamp to the end of shorter months without drifting: a monthly series
+// anchored on Jan 31 runs Feb 28, Mar 31, Apr 30. Custom periods have no fixed
+// length and report false.
+func (p BudgetPeriod) Bounds(anchor time.Time, n int) (time.Time, time.Time, bool) {
+	start, ok := p.offset(anchor, n)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	end, _ := p.offset(anchor, n+1)
+	return start, end, true
+}
+
+// offset returns anchor moved forward by n fixed-length periods.
+func (p BudgetPeriod) offset(anchor time.Time, n int) (time.Time, bool) {
+	switch p {
+	case BudgetPeriodDaily:
+		return anchor.AddDate(0, 0, n), true
+	case BudgetPeriodWeekly:
+		return anchor.AddDate(0, 0, 7*n), true
+	case BudgetPeriodBiweekly:
+		return anchor.AddDate(0, 0, 14*n), true
+	case BudgetPeriodMonthly:
+		return addMonthsClamped(anchor, n), true
+	case BudgetPeriodQuarterly:
+		return addMonthsClamped(anchor, 3*n), true
+	case BudgetPeriodYearly:
+		return addMonthsClamped(anchor, 12*n), true
+	}
+	return time.Time{}, false
+}
+
+// EndDate returns the end of a period beginning at start, which is also the
+// start of the period after it. Custom periods take their end from customEnd,
+// which must be after start; it is ignored for every other period.
+func (p BudgetPeriod) EndDate(start time.Time, customEnd *time.Time) (time.Time, error) {
+	if p == BudgetPeriodCustom {
+		if customEnd == nil || !customEnd.After(start) {
+			return time.Time{}, ErrInvalidPeriod
+		}
+		return *customEnd, nil
+	}
+
+	_, end, ok := p.Bounds(start, 0)
+	if !ok {
+		return time.Time{}, ErrInvalidPeriod
+	}
+	return end, nil
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBudgetPeriodBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		period BudgetPeriod
+		anchor time.Time
+		starts []time.Time
+	}{
+		{
+			name:   "monthly from Jan 31 keeps the month end",
+			period: BudgetPeriodMonthly,
+			anchor: date(2025, time.January, 31),
+			starts: []time.Time{
+				date(2025, time.January, 31),
+				date(2025, time.February, 28),
+				date(2025, time.March, 31),
+				date(2025, time.April, 30),
+				date(2025, time.May, 31),
+				date(2025, time.June, 30),
+			},
+		},
+		{
+			name:   "monthly from Jan 31 in a leap year",
+			period: BudgetPeriodMonthly,
+			anchor: date(2024, time.January, 31),
+			starts: []time.Time{
+				date(2024, time.January, 31),
+				date(2024, time.February, 29),
+				date(2024, time.March, 31),
+			},
+		},
+		{
+			name:   "monthly from Feb 29",
+			period: BudgetPeriodMonthly,
+			anchor: date(2024, time.February, 29),
+			starts: []time.Time{
+				date(2024, time.February, 29),
+				date(2024, time.March, 29),
+				date(2024, time.April, 29),
+			},
+		},
+		{
+			name:   "yearly from Feb 29 returns to it in leap years",
+			period: BudgetPeriodYearly,
+			anchor: date(2024, time.February, 29),
+			starts: []time.Time{
+				date(2024, time.February, 29),
+				date(2025, time.February, 28),
+				date(2026, time.February, 28),
+				date(2027, time.February, 28),
+				date(2028, time.February, 29),
+			},
+		},
+		{
+			name:   "quarterly from Nov 30",
+			period: BudgetPeriodQuarterly,
+			anchor: date(2024, time.November, 30),
+			starts: []time.Time{
+				date(2024, time.November, 30),
+				date(2025, time.February, 28),
+				date(2025, time.May, 30),
+				date(2025, time.August, 30),
+				date(2025, time.November, 30),
+			},
+		},
+		{
+			name:   "quarterly from Aug 31",
+			period: BudgetPeriodQuarterly,
+			anchor: date(2025, time.August, 31),
+			starts: []time.Time{
+				date(2025, time.August, 31),
+				date(2025, time.November, 30),
+				date(2026, time.February, 28),
+				date(2026, time.May, 31),
+			},
+		},
+		{
+			name:   "biweekly",
+			period: BudgetPeriodBiweekly,
+			anchor: date(2025, time.December, 22),
+			starts: []time.Time{
+				date(2025, time.December, 22),
+				date(2026, time.January, 5),
+				date(2026, time.January, 19),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n := 0; n < len(tt.starts)-1; n++ {
+				start, end, ok := tt.period.Bounds(tt.anchor, n)
+				if !ok {
+					t.Fatalf("Bounds(%d) not ok", n)
+				}
+				if !start.Equal(tt.starts[n]) || !end.Equal(tt.starts[n+1]) {
+					t.Errorf("Bounds(%d) = %s to %s, want %s to %s", n,
+						start.Format(time.DateOnly), end.Format(time.DateOnly),
+						tt.starts[n].Format(time.DateOnly), tt.starts[n+1].Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
+
+func TestBudgetPeriodBoundsCustom(t *testing.T) {
+	if _, _, ok := BudgetPeriodCustom.Bounds(date(2025, time.January, 1), 1); ok {
+		t.Error("custom period has bounds")
+	}
+}
+
+func TestBudgetNextPeriodDoesNotDrift(t *testing.T) {
+	anchor := date(2025, time.January, 31)
+	budget := &Budget{Period: BudgetPeriodMonthly, StartDate: anchor, PeriodAnchor: anchor}
+	budget.EndDate, _ = budget.Period.EndDate(anchor, nil)
+
+	want := []time.Time{
+		date(2025, time.February, 28),
+		date(2025, time.March, 31),
+		date(2025, time.April, 30),
+		date(2025, time.May, 31),
+	}
+	for _, wantStart := range want {
+		start, end := budget.NextPeriod()
+		if !start.Equal(wantStart) {
+			t.Fatalf("next period starts %s, want %s", start.Format(time.DateOnly), wantStart.Format(time.DateOnly))
+		}
+		if !start.Equal(budget.EndDate) {
+			t.Fatalf("next period starts %s, previous ended %s", start.Format(time.DateOnly), budget.EndDate.Format(time.DateOnly))
+		}
+
+		anchor, index := budget.PeriodPosition()
+		budget = &Budget{
+			Period:       budget.Period,
+			StartDate:    start,
+			EndDate:      end,
+			PeriodAnchor: anchor,
+			PeriodIndex:  index + 1,
+		}
+	}
+}
+
+func TestBudgetNextPeriodCustom(t *testing.T) {
+	budget := &Budget{
+		Period:    BudgetPeriodCustom,
+		StartDate: date(2025, time.March, 1),
+		EndDate:   date(2025, time.March, 11),
+	}
+
+	start, end := budget.NextPeriod()
+	if !start.Equal(date(2025, time.March, 11)) || !end.Equal(date(2025, time.March, 21)) {
+		t.Errorf("next custom period = %s to %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	}
+}
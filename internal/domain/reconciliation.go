@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ReconciliationTrigger string
+
+const (
+	ReconciliationTriggerWorker ReconciliationTrigger = "worker"
+	ReconciliationTriggerAdmin  ReconciliationTrigger = "admin"
+)
+
+// CategorySpend is the spend recomputed from the expenses collection for one
+// category of one budget.
+type CategorySpend struct {
+	BudgetID   primitive.ObjectID `bson:"budget_id" json:"budget_id"`
+	CategoryID primitive.ObjectID `bson:"category_id" json:"category_id"`
+	Total      Money              `bson:"total" json:"total"`
+}
+
+// CategoryDrift.Name is empty for categories the budget no longer tracks.
+type CategoryDrift struct {
+	CategoryID primitive.ObjectID `bson:"category_id" json:"category_id"`
+	Name       string             `bson:"name" json:"name"`
+	Stored     Money              `bson:"stored" json:"stored"`
+	Actual     Money              `bson:"actual" json:"actual"`
+}
+
+// BudgetDrift records a budget whose stored spent amounts disagree with its
+// expenses. Persistent is set when the budget had also drifted in the
+// previous run.
+type BudgetDrift struct {
+	BudgetID    primitive.ObjectID `bson:"budget_id" json:"budget_id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	BudgetName  string             `bson:"budget_name" json:"budget_name"`
+	StoredSpent Money              `bson:"stored_spent" json:"stored_spent"`
+	ActualSpent Money              `bson:"actual_spent" json:"actual_spent"`
+	Categories  []CategoryDrift    `bson:"categories" json:"categories"`
+	Persistent  bool               `bson:"persistent" json:"persistent"`
+	Repaired    bool               `bson:"repaired" json:"repaired"`
+	RepairErr   string             `bson:"repair_error,omitempty" json:"repair_error,omitempty"`
+}
+
+type ReconciliationReport struct {
+	ID              primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	Trigger         ReconciliationTrigger `bson:"trigger" json:"trigger"`
+	Repair          bool                  `bson:"repair" json:"repair"`
+	BudgetsChecked  int                   `bson:"budgets_checked" json:"budgets_checked"`
+	DriftCount      int                   `bson:"drift_count" json:"drift_count"`
+	PersistentCount int                   `bson:"persistent_count" json:"persistent_count"`
+	Drifts          []BudgetDrift         `bson:"drifts" json:"drifts"`
+	StartedAt       time.Time             `bson:"started_at" json:"started_at"`
+	FinishedAt      time.Time             `bson:"finished_at" json:"finished_at"`
+}
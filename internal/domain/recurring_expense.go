@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+	RecurrenceYearly  RecurrenceFrequency = "yearly"
+)
+
+// RecurrenceRule is a small subset of an RFC 5545 RRULE: FREQ, INTERVAL and
+// UNTIL.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `bson:"frequency" json:"frequency" validate:"required,oneof=daily weekly monthly yearly"`
+	Interval  int                 `bson:"interval" json:"interval" validate:"gte=0"`
+	Until     *time.Time          `bson:"until,omitempty" json:"until,omitempty"`
+}
+
+// Occurrence returns the nth (zero-based) occurrence of the rule counted from
+// start, or nil once the rule has passed its Until date. Monthly and yearly
+// rules clamp to the last day of shorter months instead of overflowing, so a
+// rule starting on Jan 31 occurs on Feb 28.
+func (r RecurrenceRule) Occurrence(start time.Time, n int) *time.Time {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	step := n * interval
+
+	var next time.Time
+	switch r.Frequency {
+	case RecurrenceDaily:
+		next = start.AddDate(0, 0, step)
+	case RecurrenceWeekly:
+		next = start.AddDate(0, 0, 7*step)
+	case RecurrenceMonthly:
+		next = addMonthsClamped(start, step)
+	case RecurrenceYearly:
+		next = addMonthsClamped(start, 12*step)
+	default:
+		return nil
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return nil
+	}
+
+	return &next
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfTarget := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfTarget.AddDate(0, 0, day-1)
+}
+
+// RecurringExpense amounts are charged in Currency, or in the currency of
+// the matching budget when Currency is empty. Each occurrence is charged to
+// the active budget that tracks CategoryID.
+type RecurringExpense struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID          primitive.ObjectID `bson:"user_id" json:"user_id"`
+	CategoryID      primitive.ObjectID `bson:"category_id" json:"category_id"`
+	Category        string             `bson:"category" json:"category"`
+	Amount          Money              `bson:"amount" json:"amount"`
+	Currency        Currency           `bson:"currency,omitempty" json:"currency,omitempty"`
+	Description     string             `bson:"description" json:"description"`
+	Rule            RecurrenceRule     `bson:"rule" json:"rule"`
+	StartDate       time.Time          `bson:"start_date" json:"start_date"`
+	OccurrenceCount int                `bson:"occurrence_count" json:"occurrence_count"`
+	NextOccurrence  *time.Time         `bson:"next_occurrence,omitempty" json:"next_occurrence"`
+	IsActive        bool               `bson:"is_active" json:"is_active"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CreateRecurringExpenseRequest names a catalog category by CategoryID, or
+// by Category, which is added to the catalog when missing.
+type CreateRecurringExpenseRequest struct {
+	CategoryID  string         `json:"category_id"`
+	Category    string         `json:"category" validate:"required_without=CategoryID"`
+	Amount      Money          `json:"amount" validate:"required,gt=0"`
+	Currency    Currency       `json:"currency" validate:"omitempty,iso4217"`
+	Description string         `json:"description"`
+	Rule        RecurrenceRule `json:"rule" validate:"required"`
+	StartDate   time.Time      `json:"start_date" validate:"required"`
+}
+
+type UpdateRecurringExpenseRequest struct {
+	CreateRecurringExpenseRequest
+	IsActive *bool `json:"is_active"`
+}
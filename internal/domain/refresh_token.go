@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is the server-side record of an issued refresh token. Tokens
+// issued from the same login share a FamilyID so that a reused token can
+// revoke the whole chain of rotations.
+type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TokenID    string             `bson:"token_id" json:"-"`
+	FamilyID   string             `bson:"family_id" json:"-"`
+	ReplacedBy string             `bson:"replaced_by,omitempty" json:"-"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -6,6 +6,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+type UserRole string
+
+const (
+	UserRoleUser  UserRole = "user"
+	UserRoleAdmin UserRole = "admin"
+)
+
 type User struct {
 	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Email            string             `bson:"email" json:"email"`
@@ -13,6 +20,7 @@ type User struct {
 	FirstName        string             `bson:"first_name" json:"first_name"`
 	LastName         string             `bson:"last_name" json:"last_name"`
 	IsEmailVerified  bool               `bson:"is_email_verified" json:"is_email_verified"`
+	Role             UserRole           `bson:"role,omitempty" json:"role,omitempty"`
 	ResetToken       string             `bson:"reset_token,omitempty" json:"-"`
 	ResetTokenExpiry *time.Time         `bson:"reset_token_expiry,omitempty" json:"-"`
 	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AlertHandler struct {
+	alertService service.AlertService
+	validator    *utils.Validator
+}
+
+func NewAlertHandler(alertService service.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+		validator:    utils.NewValidator(),
+	}
+}
+
+func (h *AlertHandler) CreateAlert(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	alert, err := h.alertService.CreateAlert(r.Context(), userID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, alert, http.StatusCreated)
+}
+
+func (h *AlertHandler) GetAlerts(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	alerts, err := h.alertService.GetUserAlerts(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, alerts, http.StatusOK)
+}
+
+func (h *AlertHandler) GetBudgetAlerts(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	alerts, err := h.alertService.GetBudgetAlerts(r.Context(), userID, budgetID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, alerts, http.StatusOK)
+}
+
+func (h *AlertHandler) GetAlert(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	alertID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	alert, err := h.alertService.GetAlert(r.Context(), userID, alertID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, alert, http.StatusOK)
+}
+
+func (h *AlertHandler) UpdateAlert(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	alertID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.CreateAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	alert, err := h.alertService.UpdateAlert(r.Context(), userID, alertID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, alert, http.StatusOK)
+}
+
+func (h *AlertHandler) EnableAlert(w http.ResponseWriter, r *http.Request) {
+	h.setAlertEnabled(w, r, true)
+}
+
+func (h *AlertHandler) DisableAlert(w http.ResponseWriter, r *http.Request) {
+	h.setAlertEnabled(w, r, false)
+}
+
+func (h *AlertHandler) DeleteAlert(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	alertID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.alertService.DeleteAlert(r.Context(), userID, alertID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Alert deleted successfully",
+	}, http.StatusOK)
+}
+
+func (h *AlertHandler) setAlertEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	alertID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	alert, err := h.alertService.SetAlertEnabled(r.Context(), userID, alertID, enabled)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, alert, http.StatusOK)
+}
+
+func (h *AlertHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrAlertNotFound, domain.ErrBudgetNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrInvalidInput, domain.ErrInvalidTiers, domain.ErrCategoryNotFound:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
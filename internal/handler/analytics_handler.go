@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultTopDescriptions = 10
+	maxTopDescriptions     = 100
+	defaultComparedPeriods = 6
+	maxComparedPeriods     = 24
+)
+
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+func (h *AnalyticsHandler) GetSpendByCategory(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	if value := r.URL.Query().Get("rollup"); value != "" {
+		rollup, err := strconv.ParseBool(value)
+		if err != nil {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		query.Rollup = rollup
+	}
+
+	totals, err := h.analyticsService.SpendByCategory(r.Context(), query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, totals, http.StatusOK)
+}
+
+func (h *AnalyticsHandler) GetSpendOverTime(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	switch interval := domain.AnalyticsInterval(r.URL.Query().Get("interval")); interval {
+	case "":
+		query.Interval = domain.AnalyticsIntervalMonth
+	case domain.AnalyticsIntervalDay, domain.AnalyticsIntervalWeek, domain.AnalyticsIntervalMonth:
+		query.Interval = interval
+	default:
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	points, err := h.analyticsService.SpendOverTime(r.Context(), query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, points, http.StatusOK)
+}
+
+func (h *AnalyticsHandler) GetTopDescriptions(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.parseQuery(w, r)
+	if !ok {
+		return
+	}
+
+	query.Limit = defaultTopDescriptions
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxTopDescriptions {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+		query.Limit = n
+	}
+
+	totals, err := h.analyticsService.TopDescriptions(r.Context(), query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, totals, http.StatusOK)
+}
+
+func (h *AnalyticsHandler) GetBudgetVsActual(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	periods := defaultComparedPeriods
+	if value := r.URL.Query().Get("periods"); value != "" {
+		periods, err = strconv.Atoi(value)
+		if err != nil || periods < 1 || periods > maxComparedPeriods {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.analyticsService.BudgetVsActual(r.Context(), userID, budgetID, periods)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, report, http.StatusOK)
+}
+
+// parseQuery reads the caller and the scope and date range parameters shared
+// by the spending reports, writing an error response when they are invalid.
+func (h *AnalyticsHandler) parseQuery(w http.ResponseWriter, r *http.Request) (*domain.AnalyticsQuery, bool) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return nil, false
+	}
+
+	query, err := parseAnalyticsQuery(r.URL.Query())
+	if err != nil {
+		response.Error(w, err, http.StatusBadRequest)
+		return nil, false
+	}
+	query.UserID = userID
+
+	return query, true
+}
+
+func parseAnalyticsQuery(values url.Values) (*domain.AnalyticsQuery, error) {
+	query := &domain.AnalyticsQuery{Timezone: "UTC"}
+
+	if value := values.Get("budget_id"); value != "" {
+		budgetID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, domain.ErrInvalidObjectID
+		}
+		query.BudgetID = &budgetID
+	}
+
+	var err error
+	if query.From, err = parseDateParam(values.Get("from"), false); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseDateParam(values.Get("to"), true); err != nil {
+		return nil, err
+	}
+
+	if tz := values.Get("tz"); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		query.Timezone = tz
+	}
+
+	return query, nil
+}
+
+func (h *AnalyticsHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrBudgetNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrInvalidInput:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
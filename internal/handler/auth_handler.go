@@ -72,6 +72,57 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, result, http.StatusOK)
 }
 
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req domain.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.authService.RefreshToken(r.Context(), req.RefreshToken)
+	if err != nil {
+		if err == domain.ErrInvalidToken || err == domain.ErrTokenExpired || err == domain.ErrTokenReused {
+			response.Error(w, err, http.StatusUnauthorized)
+			return
+		}
+		response.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(w, result, http.StatusOK)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req domain.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), req.RefreshToken); err != nil {
+		if err == domain.ErrInvalidToken {
+			response.Error(w, err, http.StatusUnauthorized)
+			return
+		}
+		response.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Logged out successfully",
+	}, http.StatusOK)
+}
+
 func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var req domain.ForgotPasswordRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
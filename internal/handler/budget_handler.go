@@ -10,6 +10,7 @@ import (
 	"github.com/dmehra2102/budget-tracker/internal/utils"
 	"github.com/dmehra2102/budget-tracker/pkg/logger"
 	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -46,10 +47,15 @@ func (h *BudgetHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
 
 	budget, err := h.budgetService.CreateBudget(r.Context(), userID, &req)
 	if err != nil {
-		response.Error(w, err, http.StatusInternalServerError)
+		if err == domain.ErrInvalidPeriod || err == domain.ErrInvalidInput || err == domain.ErrCategoryNotFound || err == domain.ErrCategoryArchived {
+			response.Error(w, err, http.StatusBadRequest)
+		} else {
+			response.Error(w, err, http.StatusInternalServerError)
+		}
 		return
 	}
 
+	setETag(w, budget.Version)
 	response.Success(w, budget, http.StatusCreated)
 }
 
@@ -76,7 +82,7 @@ func (h *BudgetHandler) GetBudget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	budgetIDStr := r.PathValue("id")
+	budgetIDStr := mux.Vars(r)["id"]
 	if budgetIDStr == "" {
 		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
 		return
@@ -92,12 +98,15 @@ func (h *BudgetHandler) GetBudget(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err == domain.ErrBudgetNotFound {
 			response.Error(w, err, http.StatusNotFound)
+		} else if err == domain.ErrUnauthorized {
+			response.Error(w, err, http.StatusForbidden)
 		} else {
 			response.Error(w, err, http.StatusInternalServerError)
 		}
 		return
 	}
 
+	setETag(w, budget.Version)
 	response.Success(w, budget, http.StatusOK)
 }
 
@@ -108,7 +117,7 @@ func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	budgetIDStr := r.PathValue("id")
+	budgetIDStr := mux.Vars(r)["id"]
 	if budgetIDStr == "" {
 		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
 		return
@@ -120,25 +129,102 @@ func (h *BudgetHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatch(r)
+	if err != nil {
+		response.Error(w, err, http.StatusPreconditionFailed)
+		return
+	}
+
 	var req domain.UpdateBudgetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
 		return
 	}
 
-	updatedBudget, err := h.budgetService.UpdateBudget(r.Context(), userID, budgetID, &req)
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	updatedBudget, err := h.budgetService.UpdateBudget(r.Context(), userID, budgetID, &req, version)
 	if err != nil {
-		if err == domain.ErrBudgetNotFound {
-			response.Error(w, err, http.StatusNotFound)
-		} else {
-			response.Error(w, err, http.StatusInternalServerError)
-		}
+		updateBudgetError(w, err)
+		return
+	}
+
+	setETag(w, updatedBudget.Version)
+	response.Success(w, updatedBudget, http.StatusOK)
+}
+
+// PatchBudget applies a JSON Merge Patch or JSON Patch to the budget's name,
+// carry-over setting and categories.
+func (h *BudgetHandler) PatchBudget(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		response.Error(w, err, http.StatusPreconditionFailed)
+		return
+	}
+
+	budget, err := h.budgetService.GetBudget(r.Context(), userID, budgetID)
+	if err != nil {
+		updateBudgetError(w, err)
+		return
+	}
+
+	if version != nil && *version != budget.Version {
+		response.Error(w, domain.ErrVersionConflict, http.StatusPreconditionFailed)
+		return
+	}
+
+	var doc domain.BudgetDocument
+	if err := decodePatch(r, budget.Document(), &doc); err != nil {
+		patchError(w, err)
+		return
+	}
+
+	if err := h.validator.Validate(&doc); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	updatedBudget, err := h.budgetService.UpdateBudget(r.Context(), userID, budgetID, doc.UpdateRequest(), &budget.Version)
+	if err != nil {
+		updateBudgetError(w, err)
 		return
 	}
 
+	setETag(w, updatedBudget.Version)
 	response.Success(w, updatedBudget, http.StatusOK)
 }
 
+func updateBudgetError(w http.ResponseWriter, err error) {
+	if err == domain.ErrBudgetNotFound {
+		response.Error(w, err, http.StatusNotFound)
+	} else if err == domain.ErrUnauthorized {
+		response.Error(w, err, http.StatusForbidden)
+	} else if err == domain.ErrVersionConflict {
+		response.Error(w, err, http.StatusPreconditionFailed)
+	} else if err == domain.ErrCategoryInUse {
+		response.Error(w, err, http.StatusConflict)
+	} else if err == domain.ErrInvalidInput || err == domain.ErrCategoryNotFound || err == domain.ErrCategoryArchived {
+		response.Error(w, err, http.StatusBadRequest)
+	} else {
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
+
 func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
 	userID, err := middleware.GetUserIDFromContext(r.Context())
 	if err != nil {
@@ -146,7 +232,7 @@ func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	budgetIDStr := r.PathValue("id")
+	budgetIDStr := mux.Vars(r)["id"]
 	if budgetIDStr == "" {
 		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
 		return
@@ -161,6 +247,8 @@ func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
 	if err := h.budgetService.DeleteBudget(r.Context(), userID, budgetID); err != nil {
 		if err == domain.ErrBudgetNotFound {
 			response.Error(w, err, http.StatusNotFound)
+		} else if err == domain.ErrUnauthorized {
+			response.Error(w, err, http.StatusForbidden)
 		} else {
 			response.Error(w, err, http.StatusInternalServerError)
 		}
@@ -171,3 +259,44 @@ func (h *BudgetHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
 		"message": "Budget deleted successfully",
 	}, http.StatusOK)
 }
+
+func (h *BudgetHandler) CloneBudget(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.NewBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	budget, err := h.budgetService.CloneBudget(r.Context(), userID, budgetID, &req)
+	if err != nil {
+		if err == domain.ErrBudgetNotFound {
+			response.Error(w, err, http.StatusNotFound)
+		} else if err == domain.ErrUnauthorized {
+			response.Error(w, err, http.StatusForbidden)
+		} else if err == domain.ErrInvalidPeriod || err == domain.ErrInvalidInput || err == domain.ErrCategoryNotFound || err == domain.ErrCategoryArchived {
+			response.Error(w, err, http.StatusBadRequest)
+		} else {
+			response.Error(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.Success(w, budget, http.StatusCreated)
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BudgetMemberHandler struct {
+	memberService service.BudgetMemberService
+	validator     *utils.Validator
+}
+
+func NewBudgetMemberHandler(memberService service.BudgetMemberService) *BudgetMemberHandler {
+	return &BudgetMemberHandler{
+		memberService: memberService,
+		validator:     utils.NewValidator(),
+	}
+}
+
+func (h *BudgetMemberHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.InviteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	invitation, err := h.memberService.InviteMember(r.Context(), userID, budgetID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, invitation, http.StatusCreated)
+}
+
+func (h *BudgetMemberHandler) GetBudgetInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	invitations, err := h.memberService.GetBudgetInvitations(r.Context(), userID, budgetID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, invitations, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	budgetID, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(vars["invitationId"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.memberService.RevokeInvitation(r.Context(), userID, budgetID, invitationID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Invitation revoked successfully",
+	}, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) GetInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	invitations, err := h.memberService.GetUserInvitations(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, invitations, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	budget, err := h.memberService.AcceptInvitation(r.Context(), userID, req.Token)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, budget, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.memberService.GetMembers(r.Context(), userID, budgetID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, members, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	budgetID, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(vars["userId"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.UpdateMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if err := h.memberService.UpdateMemberRole(r.Context(), userID, budgetID, memberID, &req); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Member updated successfully",
+	}, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	budgetID, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(vars["userId"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.memberService.RemoveMember(r.Context(), userID, budgetID, memberID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Member removed successfully",
+	}, http.StatusOK)
+}
+
+func (h *BudgetMemberHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrBudgetNotFound, domain.ErrMemberNotFound, domain.ErrInvitationNotFound, domain.ErrUserNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrAlreadyMember:
+		response.Error(w, err, http.StatusConflict)
+	case domain.ErrInvalidInput:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BudgetTemplateHandler struct {
+	templateService service.BudgetTemplateService
+	budgetService   service.BudgetService
+	validator       *utils.Validator
+}
+
+func NewBudgetTemplateHandler(templateService service.BudgetTemplateService, budgetService service.BudgetService) *BudgetTemplateHandler {
+	return &BudgetTemplateHandler{
+		templateService: templateService,
+		budgetService:   budgetService,
+		validator:       utils.NewValidator(),
+	}
+}
+
+func (h *BudgetTemplateHandler) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateBudgetTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(r.Context(), userID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, template, http.StatusCreated)
+}
+
+func (h *BudgetTemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	templates, err := h.templateService.GetUserTemplates(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, templates, http.StatusOK)
+}
+
+func (h *BudgetTemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(r.Context(), userID, templateID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, template, http.StatusOK)
+}
+
+func (h *BudgetTemplateHandler) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.CreateBudgetTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(r.Context(), userID, templateID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, template, http.StatusOK)
+}
+
+func (h *BudgetTemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(r.Context(), userID, templateID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Budget template deleted successfully",
+	}, http.StatusOK)
+}
+
+func (h *BudgetTemplateHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.NewBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	budget, err := h.budgetService.CreateBudgetFromTemplate(r.Context(), userID, templateID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, budget, http.StatusCreated)
+}
+
+func (h *BudgetTemplateHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrTemplateNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrInvalidInput, domain.ErrInvalidPeriod, domain.ErrCategoryNotFound, domain.ErrCategoryArchived:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryService
+	validator       *utils.Validator
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		validator:       utils.NewValidator(),
+	}
+}
+
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	category, err := h.categoryService.CreateCategory(r.Context(), userID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, category, http.StatusCreated)
+}
+
+// GetCategories lists the user's catalog. Archived categories are left out
+// unless archived=true is given.
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	includeArchived := false
+	if value := r.URL.Query().Get("archived"); value != "" {
+		includeArchived, err = strconv.ParseBool(value)
+		if err != nil {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+	}
+
+	categories, err := h.categoryService.GetUserCategories(r.Context(), userID, includeArchived)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, categories, http.StatusOK)
+}
+
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	categoryID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.categoryService.GetCategory(r.Context(), userID, categoryID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, category, http.StatusOK)
+}
+
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	categoryID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.UpdateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	category, err := h.categoryService.UpdateCategory(r.Context(), userID, categoryID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, category, http.StatusOK)
+}
+
+func (h *CategoryHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrCategoryNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrCategoryExists:
+		response.Error(w, err, http.StatusConflict)
+	case domain.ErrInvalidInput, domain.ErrInvalidParent, domain.ErrCategoryArchived:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EnvelopeHandler struct {
+	envelopeService service.EnvelopeService
+	validator       *utils.Validator
+}
+
+func NewEnvelopeHandler(envelopeService service.EnvelopeService) *EnvelopeHandler {
+	return &EnvelopeHandler{
+		envelopeService: envelopeService,
+		validator:       utils.NewValidator(),
+	}
+}
+
+func (h *EnvelopeHandler) RecordIncome(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.RecordIncomeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	transfer, err := h.envelopeService.RecordIncome(r.Context(), userID, budgetID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, transfer, http.StatusCreated)
+}
+
+func (h *EnvelopeHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	var req domain.EnvelopeTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	transfer, err := h.envelopeService.Transfer(r.Context(), userID, budgetID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, transfer, http.StatusCreated)
+}
+
+func (h *EnvelopeHandler) GetTransfers(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	transfers, err := h.envelopeService.GetTransfers(r.Context(), userID, budgetID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, transfers, http.StatusOK)
+}
+
+func (h *EnvelopeHandler) GetEnvelopes(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.envelopeService.GetEnvelopes(r.Context(), userID, budgetID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, summary, http.StatusOK)
+}
+
+func (h *EnvelopeHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrBudgetNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrInvalidInput, domain.ErrCategoryNotFound:
+		response.Error(w, err, http.StatusBadRequest)
+	case domain.ErrNotEnvelopeBudget:
+		response.Error(w, err, http.StatusConflict)
+	case domain.ErrInsufficientFunds:
+		response.Error(w, err, http.StatusUnprocessableEntity)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
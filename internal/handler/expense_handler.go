@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ExpenseHandler struct {
+	expenseService service.ExpenseService
+	validator      *utils.Validator
+}
+
+func NewExpenseHandler(expenseService service.ExpenseService) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+		validator:      utils.NewValidator(),
+	}
+}
+
+func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	expense, err := h.expenseService.CreateExpense(r.Context(), userID, &req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	setETag(w, expense.Version)
+	response.Success(w, expense, http.StatusCreated)
+}
+
+func (h *ExpenseHandler) GetExpenses(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	query, err := parseExpenseQuery(r.URL.Query())
+	if err != nil {
+		response.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	expenses, err := h.expenseService.GetUserExpenses(r.Context(), userID, query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, expenses, http.StatusOK)
+}
+
+func (h *ExpenseHandler) GetBudgetExpenses(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseExpenseQuery(r.URL.Query())
+	if err != nil {
+		response.Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	expenses, err := h.expenseService.GetExpensesByBudget(r.Context(), userID, budgetID, query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, expenses, http.StatusOK)
+}
+
+func (h *ExpenseHandler) GetExpense(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	expenseID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	expense, err := h.expenseService.GetExpense(r.Context(), userID, expenseID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	setETag(w, expense.Version)
+	response.Success(w, expense, http.StatusOK)
+}
+
+func (h *ExpenseHandler) UpdateExpense(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	expenseID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	var req domain.CreateExpenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	expense, err := h.expenseService.UpdateExpense(r.Context(), userID, expenseID, &req, version)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	setETag(w, expense.Version)
+	response.Success(w, expense, http.StatusOK)
+}
+
+// PatchExpense applies a JSON Merge Patch or JSON Patch to the expense. The
+// budget an expense belongs to cannot be changed.
+func (h *ExpenseHandler) PatchExpense(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	expenseID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	expense, err := h.expenseService.GetExpense(r.Context(), userID, expenseID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if version != nil && *version != expense.Version {
+		h.handleError(w, domain.ErrVersionConflict)
+		return
+	}
+
+	var req domain.CreateExpenseRequest
+	if err := decodePatch(r, expense.Document(), &req); err != nil {
+		patchError(w, err)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	if req.BudgetID != expense.BudgetID.Hex() {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	expense, err = h.expenseService.UpdateExpense(r.Context(), userID, expenseID, &req, &expense.Version)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	setETag(w, expense.Version)
+	response.Success(w, expense, http.StatusOK)
+}
+
+func (h *ExpenseHandler) DeleteExpense(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	expenseID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.expenseService.DeleteExpense(r.Context(), userID, expenseID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]string{
+		"message": "Expense deleted successfully",
+	}, http.StatusOK)
+}
+
+func (h *ExpenseHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrExpenseNotFound, domain.ErrBudgetNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrVersionConflict:
+		response.Error(w, err, http.StatusPreconditionFailed)
+	case domain.ErrInvalidInput, domain.ErrInvalidCursor, domain.ErrCategoryNotFound:
+		response.Error(w, err, http.StatusBadRequest)
+	case domain.ErrRateNotFound:
+		response.Error(w, err, http.StatusUnprocessableEntity)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
+
+// parseExpenseQuery reads the filter, sort and pagination parameters shared
+// by the expense listing endpoints. Results default to newest first.
+func parseExpenseQuery(values url.Values) (*domain.ExpenseQuery, error) {
+	query := &domain.ExpenseQuery{
+		Search:   values.Get("q"),
+		Cursor:   values.Get("cursor"),
+		SortBy:   domain.ExpenseSortDate,
+		SortDesc: true,
+	}
+
+	if categoryID := values.Get("category_id"); categoryID != "" {
+		id, err := primitive.ObjectIDFromHex(categoryID)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		query.CategoryID = &id
+	}
+
+	var err error
+	if query.From, err = parseDateParam(values.Get("from"), false); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseDateParam(values.Get("to"), true); err != nil {
+		return nil, err
+	}
+	if query.MinAmount, err = parseAmountParam(values.Get("min_amount")); err != nil {
+		return nil, err
+	}
+	if query.MaxAmount, err = parseAmountParam(values.Get("max_amount")); err != nil {
+		return nil, err
+	}
+
+	switch sort := values.Get("sort"); sort {
+	case "":
+	case string(domain.ExpenseSortDate), string(domain.ExpenseSortAmount):
+		query.SortBy = domain.ExpenseSortField(sort)
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	switch values.Get("order") {
+	case "", "desc":
+	case "asc":
+		query.SortDesc = false
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 {
+			return nil, domain.ErrInvalidInput
+		}
+	}
+
+	return query, nil
+}
+
+// parseDateParam accepts either an RFC 3339 timestamp or a plain date. A plain
+// date used as an upper bound covers the whole day.
+func parseDateParam(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		if endOfDay {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
+
+	return &t, nil
+}
+
+func parseAmountParam(value string) (*domain.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	amount, err := domain.ParseMoney(value)
+	if err != nil || amount < 0 {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return &amount, nil
+}
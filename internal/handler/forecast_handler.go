@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ForecastHandler struct {
+	forecastService service.ForecastService
+}
+
+func NewForecastHandler(forecastService service.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		forecastService: forecastService,
+	}
+}
+
+func (h *ForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	budgetID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	forecast, err := h.forecastService.GetForecast(r.Context(), userID, budgetID)
+	if err != nil {
+		switch err {
+		case domain.ErrBudgetNotFound:
+			response.Error(w, err, http.StatusNotFound)
+		case domain.ErrUnauthorized:
+			response.Error(w, err, http.StatusForbidden)
+		case domain.ErrRateNotFound:
+			response.Error(w, err, http.StatusUnprocessableEntity)
+		default:
+			response.Error(w, err, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.Success(w, forecast, http.StatusOK)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/middleware"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/internal/utils"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+	validator           *utils.Validator
+}
+
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		validator:           utils.NewValidator(),
+	}
+}
+
+// GetNotifications lists the user's inbox, newest first. unread=true leaves
+// out notifications already read.
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	values := r.URL.Query()
+	query := &domain.NotificationQuery{Cursor: values.Get("cursor")}
+	if unread := values.Get("unread"); unread != "" {
+		query.UnreadOnly, err = strconv.ParseBool(unread)
+		if err != nil {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+	}
+	if limit := values.Get("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.notificationService.GetInbox(r.Context(), userID, query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	for _, notification := range page.Items {
+		linkNotification(notification)
+	}
+
+	response.Success(w, page, http.StatusOK)
+}
+
+func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	count, err := h.notificationService.GetUnreadCount(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]int64{
+		"unread_count": count,
+	}, http.StatusOK)
+}
+
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	h.markNotification(w, r, true)
+}
+
+func (h *NotificationHandler) MarkUnread(w http.ResponseWriter, r *http.Request) {
+	h.markNotification(w, r, false)
+}
+
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	h.markNotifications(w, r, true)
+}
+
+func (h *NotificationHandler) MarkAllUnread(w http.ResponseWriter, r *http.Request) {
+	h.markNotifications(w, r, false)
+}
+
+func (h *NotificationHandler) markNotification(w http.ResponseWriter, r *http.Request, read bool) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	notificationID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		response.Error(w, domain.ErrInvalidObjectID, http.StatusBadRequest)
+		return
+	}
+
+	notification, err := h.notificationService.MarkNotification(r.Context(), userID, notificationID, read)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	linkNotification(notification)
+	response.Success(w, notification, http.StatusOK)
+}
+
+func (h *NotificationHandler) markNotifications(w http.ResponseWriter, r *http.Request, read bool) {
+	userID, err := middleware.GetUserIDFromContext(r.Context())
+	if err != nil {
+		response.Error(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.MarkNotificationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err)
+		return
+	}
+
+	updated, err := h.notificationService.MarkNotifications(r.Context(), userID, &req, read)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, map[string]int64{
+		"updated": updated,
+	}, http.StatusOK)
+}
+
+func (h *NotificationHandler) handleError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrNotificationNotFound:
+		response.Error(w, err, http.StatusNotFound)
+	case domain.ErrUnauthorized:
+		response.Error(w, err, http.StatusForbidden)
+	case domain.ErrInvalidInput, domain.ErrInvalidCursor:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
+
+func linkNotification(notification *domain.AlertNotification) {
+	notification.Links = &domain.NotificationLinks{
+		Budget: "/api/v1/budgets/" + notification.BudgetID.Hex(),
+		Alert:  "/api/v1/alerts/" + notification.AlertID.Hex(),
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/pkg/patch"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+)
+
+var acceptPatch = patch.MediaTypeMergePatch + ", " + patch.MediaTypeJSONPatch
+
+// setETag sets the ETag header to the version of the returned resource.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatch returns the version required by the If-Match header, or nil when
+// the header is missing or "*". Only a single strong entity tag is
+// understood; any other value cannot match and yields
+// domain.ErrVersionConflict.
+func ifMatch(r *http.Request) (*int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return nil, domain.ErrVersionConflict
+	}
+
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil {
+		return nil, domain.ErrVersionConflict
+	}
+	return &version, nil
+}
+
+// decodePatch applies the request body, a JSON Merge Patch or JSON Patch
+// chosen by Content-Type, to the JSON form of current and decodes the result
+// into target.
+func decodePatch(r *http.Request, current, target any) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return domain.ErrInvalidInput
+	}
+
+	patched, err := patch.Apply(r.Header.Get("Content-Type"), doc, body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(patched, target); err != nil {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
+// patchError writes the response for an error returned by decodePatch.
+func patchError(w http.ResponseWriter, err error) {
+	switch err {
+	case patch.ErrUnsupportedMediaType:
+		w.Header().Set("Accept-Patch", acceptPatch)
+		response.Error(w, err, http.StatusUnsupportedMediaType)
+	case patch.ErrTestFailed:
+		response.Error(w, err, http.StatusConflict)
+	case patch.ErrPathNotFound:
+		response.Error(w, err, http.StatusUnprocessableEntity)
+	case patch.ErrInvalidPatch, domain.ErrInvalidInput:
+		response.Error(w, err, http.StatusBadRequest)
+	default:
+		response.Error(w, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+)
+
+type ReconciliationHandler struct {
+	reconciliationService service.ReconciliationService
+}
+
+func NewReconciliationHandler(reconciliationService service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+func (h *ReconciliationHandler) RunReconciliation(w http.ResponseWriter, r *http.Request) {
+	repair := false
+	if value := r.URL.Query().Get("repair"); value != "" {
+		var err error
+		repair, err = strconv.ParseBool(value)
+		if err != nil {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.reconciliationService.Reconcile(r.Context(), domain.ReconciliationTriggerAdmin, repair)
+	if err != nil {
+		response.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(w, report, http.StatusOK)
+}
+
+func (h *ReconciliationHandler) GetReports(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 100 {
+			response.Error(w, domain.ErrInvalidInput, http.StatusBadRequest)
+			return
+		}
+	}
+
+	reports, err := h.reconciliationService.GetRecentReports(r.Context(), limit)
+	if err != nil {
+		response.Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response.Success(w, reports, http.StatusOK)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"github.com/dmehra2102/budget-tracker/pkg/response"
+)
+
+// AdminMiddleware only lets through users with the admin role. It must run
+// after AuthMiddleware. The role is read from the database rather than the
+// token so that revoking it takes effect immediately.
+func AdminMiddleware(userRepo repository.UserRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := GetUserIDFromContext(r.Context())
+			if err != nil {
+				response.Error(w, err, http.StatusUnauthorized)
+				return
+			}
+
+			user, err := userRepo.FindByID(r.Context(), userID)
+			if err != nil {
+				if err == domain.ErrUserNotFound {
+					response.Error(w, domain.ErrUnauthorized, http.StatusUnauthorized)
+					return
+				}
+				response.Error(w, err, http.StatusInternalServerError)
+				return
+			}
+
+			if user.Role != domain.UserRoleAdmin {
+				response.Error(w, domain.ErrUnauthorized, http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -15,9 +15,14 @@ type AlertRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Alert, error)
 	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Alert, error)
 	FindByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.Alert, error)
-	Update(ctx context.Context, alert *domain.Alert) error
+	Update(ctx context.Context, alert *domain.Alert, resetTiers bool) error
+	SetEnabled(ctx context.Context, id primitive.ObjectID, enabled bool) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	FindActiveAlerts(ctx context.Context) ([]*domain.Alert, error)
+	FindActiveByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.Alert, error)
+	ClaimTier(ctx context.Context, id primitive.ObjectID, periodStart time.Time, tier domain.Money, tiers []domain.Money) (bool, error)
+	ReleaseTier(ctx context.Context, id primitive.ObjectID, periodStart time.Time, tier domain.Money) error
+	MoveToBudget(ctx context.Context, fromID, toID primitive.ObjectID) error
 	CreateNotification(ctx context.Context, notification *domain.AlertNotification) error
 }
 
@@ -87,20 +92,69 @@ func (r *alertRepository) FindByBudgetID(ctx context.Context, budgetID primitive
 	return alerts, nil
 }
 
-func (r *alertRepository) Update(ctx context.Context, alert *domain.Alert) error {
+// Update writes the editable fields of the alert. The fired tiers and period
+// are left to ClaimTier, which may run concurrently, unless resetTiers is set
+// because the alert now watches something else.
+func (r *alertRepository) Update(ctx context.Context, alert *domain.Alert, resetTiers bool) error {
 	alert.UpdatedAt = time.Now()
 
-	_, err := r.collection.UpdateOne(
-		ctx,
-		bson.M{"_id": alert.ID},
-		bson.M{"$set": alert},
-	)
-	return err
+	set := bson.M{
+		"budget_id":  alert.BudgetID,
+		"type":       alert.Type,
+		"tiers":      alert.Tiers,
+		"channels":   alert.Channels,
+		"updated_at": alert.UpdatedAt,
+	}
+	unset := bson.M{}
+	if alert.CategoryID != nil {
+		set["category_id"] = *alert.CategoryID
+	} else {
+		unset["category_id"] = ""
+	}
+	if resetTiers {
+		set["fired_tiers"] = bson.A{}
+		unset["period_start"] = ""
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": alert.ID}, bson.M{"$set": set, "$unset": unset})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
+}
+
+func (r *alertRepository) SetEnabled(ctx context.Context, id primitive.ObjectID, enabled bool) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"is_enabled": enabled,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
 }
 
 func (r *alertRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"id": id})
-	return err
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrAlertNotFound
+	}
+
+	return nil
 }
 
 func (r *alertRepository) FindActiveAlerts(ctx context.Context) ([]*domain.Alert, error) {
@@ -111,12 +165,105 @@ func (r *alertRepository) FindActiveAlerts(ctx context.Context) ([]*domain.Alert
 	defer cursor.Close(ctx)
 
 	var alerts []*domain.Alert
-	if err := cursor.All(ctx, alerts); err != nil {
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *alertRepository) FindActiveByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.Alert, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"budget_id": budgetID, "is_enabled": true})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []*domain.Alert
+	if err := cursor.All(ctx, &alerts); err != nil {
 		return nil, err
 	}
 	return alerts, nil
 }
 
+// ClaimTier marks tiers as fired in the budget period starting at
+// periodStart, provided tier has not fired in it yet. It reports whether the
+// claim succeeded; only the caller that claims a tier may notify for it, so
+// concurrent evaluations of the same alert never send a tier twice. Alerts
+// still on an earlier period start the new one with no tiers fired.
+func (r *alertRepository) ClaimTier(ctx context.Context, id primitive.ObjectID, periodStart time.Time, tier domain.Money, tiers []domain.Money) (bool, error) {
+	filter, update := tierResetQuery(id, periodStart, time.Now())
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return false, err
+	}
+
+	filter, update = tierClaimQuery(id, periodStart, tier, tiers, time.Now())
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// tierResetQuery matches the alert only while it is still on an earlier
+// period and moves it to periodStart with no tiers fired.
+func tierResetQuery(id primitive.ObjectID, periodStart, now time.Time) (bson.M, bson.M) {
+	filter := bson.M{"_id": id, "period_start": bson.M{"$ne": periodStart}}
+	update := bson.M{"$set": bson.M{
+		"period_start": periodStart,
+		"fired_tiers":  []domain.Money{},
+		"updated_at":   now,
+	}}
+	return filter, update
+}
+
+// tierClaimQuery matches the alert only when it is on periodStart and tier
+// has not fired in it, and records every tier in tiers as fired.
+func tierClaimQuery(id primitive.ObjectID, periodStart time.Time, tier domain.Money, tiers []domain.Money, now time.Time) (bson.M, bson.M) {
+	filter := bson.M{
+		"_id":          id,
+		"period_start": periodStart,
+		"fired_tiers":  bson.M{"$ne": tier},
+	}
+	update := bson.M{
+		"$addToSet": bson.M{"fired_tiers": bson.M{"$each": tiers}},
+		"$set":      bson.M{"updated_at": now},
+	}
+	return filter, update
+}
+
+// ReleaseTier undoes the claim on tier when its notification could not be
+// delivered, so it fires again on the next evaluation. Lower tiers claimed
+// with it stay fired; the retried notification covers them.
+func (r *alertRepository) ReleaseTier(ctx context.Context, id primitive.ObjectID, periodStart time.Time, tier domain.Money) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "period_start": periodStart},
+		bson.M{
+			"$pull": bson.M{"fired_tiers": tier},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+// MoveToBudget points the alerts of a budget at the budget of its next
+// period, where none of their tiers have fired yet.
+func (r *alertRepository) MoveToBudget(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"budget_id": fromID},
+		bson.M{
+			"$set": bson.M{
+				"budget_id":   toID,
+				"fired_tiers": []domain.Money{},
+				"updated_at":  time.Now(),
+			},
+			"$unset": bson.M{"period_start": ""},
+		},
+	)
+	return err
+}
+
 func (r *alertRepository) CreateNotification(ctx context.Context, notification *domain.AlertNotification) error {
 	notification.SentAt = time.Now()
 
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTierResetQuery(t *testing.T) {
+	id := primitive.NewObjectID()
+	periodStart := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	now := periodStart.Add(time.Hour)
+
+	filter, update := tierResetQuery(id, periodStart, now)
+
+	wantFilter := bson.M{"_id": id, "period_start": bson.M{"$ne": periodStart}}
+	if !reflect.DeepEqual(filter, wantFilter) {
+		t.Errorf("filter = %v, want %v", filter, wantFilter)
+	}
+	wantUpdate := bson.M{"$set": bson.M{
+		"period_start": periodStart,
+		"fired_tiers":  []domain.Money{},
+		"updated_at":   now,
+	}}
+	if !reflect.DeepEqual(update, wantUpdate) {
+		t.Errorf("update = %v, want %v", update, wantUpdate)
+	}
+}
+
+func TestTierClaimQuery(t *testing.T) {
+	id := primitive.NewObjectID()
+	periodStart := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	now := periodStart.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		tier  domain.Money
+		tiers []domain.Money
+	}{
+		{name: "single tier", tier: 8000, tiers: []domain.Money{8000}},
+		{name: "highest tier claims the lower ones", tier: 10000, tiers: []domain.Money{5000, 8000, 10000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, update := tierClaimQuery(id, periodStart, tt.tier, tt.tiers, now)
+
+			wantFilter := bson.M{
+				"_id":          id,
+				"period_start": periodStart,
+				"fired_tiers":  bson.M{"$ne": tt.tier},
+			}
+			if !reflect.DeepEqual(filter, wantFilter) {
+				t.Errorf("filter = %v, want %v", filter, wantFilter)
+			}
+			wantUpdate := bson.M{
+				"$addToSet": bson.M{"fired_tiers": bson.M{"$each": tt.tiers}},
+				"$set":      bson.M{"updated_at": now},
+			}
+			if !reflect.DeepEqual(update, wantUpdate) {
+				t.Errorf("update = %v, want %v", update, wantUpdate)
+			}
+		})
+	}
+}
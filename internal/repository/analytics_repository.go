@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AnalyticsRepository runs reporting aggregations over the expenses
+// collection.
+type AnalyticsRepository interface {
+	SpendByCategory(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.CategoryTotal, error)
+	SpendOverTime(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.SpendPoint, error)
+	TopDescriptions(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.DescriptionTotal, error)
+}
+
+type analyticsRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAnalyticsRepository(db *mongo.Database) AnalyticsRepository {
+	return &analyticsRepository{
+		collection: db.Collection("expenses"),
+	}
+}
+
+func (r *analyticsRepository) SpendByCategory(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.CategoryTotal, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: analyticsMatch(query)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      bson.M{"category_id": "$category_id", "currency": "$currency"},
+			"category": bson.M{"$last": "$category"},
+			"total":    bson.M{"$sum": "$amount"},
+			"count":    bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"category_id": "$_id.category_id",
+			"category":    1,
+			"currency":    "$_id.currency",
+			"total":       1,
+			"count":       1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "category", Value: 1}}}},
+	}
+
+	var totals []domain.CategoryTotal
+	if err := r.aggregate(ctx, pipeline, &totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// SpendOverTime buckets spend by the start of each day, Monday-based week or
+// month in the query's timezone.
+func (r *analyticsRepository) SpendOverTime(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.SpendPoint, error) {
+	period := bson.M{
+		"date":     "$date",
+		"unit":     string(query.Interval),
+		"timezone": query.Timezone,
+	}
+	if query.Interval == domain.AnalyticsIntervalWeek {
+		period["startOfWeek"] = "monday"
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: analyticsMatch(query)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"period": bson.M{"$dateTrunc": period}, "currency": "$currency"},
+			"total": bson.M{"$sum": "$amount"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"period":   "$_id.period",
+			"currency": "$_id.currency",
+			"total":    1,
+			"count":    1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "period", Value: 1}, {Key: "currency", Value: 1}}}},
+	}
+
+	var points []domain.SpendPoint
+	if err := r.aggregate(ctx, pipeline, &points); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// TopDescriptions ranks expense descriptions, which usually name the merchant,
+// by total spend. Descriptions are compared ignoring case and surrounding
+// whitespace, and expenses without one are left out.
+func (r *analyticsRepository) TopDescriptions(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.DescriptionTotal, error) {
+	match := analyticsMatch(query)
+	match["description"] = bson.M{"$nin": bson.A{"", nil}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"key":      bson.M{"$toLower": bson.M{"$trim": bson.M{"input": "$description"}}},
+				"currency": "$currency",
+			},
+			"description": bson.M{"$first": bson.M{"$trim": bson.M{"input": "$description"}}},
+			"total":       bson.M{"$sum": "$amount"},
+			"count":       bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"_id.key": bson.M{"$ne": ""}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total", Value: -1}, {Key: "_id.key", Value: 1}}}},
+		{{Key: "$limit", Value: query.Limit}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"description": 1,
+			"currency":    "$_id.currency",
+			"total":       1,
+			"count":       1,
+		}}},
+	}
+
+	var totals []domain.DescriptionTotal
+	if err := r.aggregate(ctx, pipeline, &totals); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func (r *analyticsRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, results any) error {
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, results)
+}
+
+// analyticsMatch scopes the aggregation the same way as expense queries, so
+// the budget_id index is used.
+func analyticsMatch(query *domain.AnalyticsQuery) bson.M {
+	match := bson.M{}
+	if query.BudgetID != nil {
+		match["budget_id"] = *query.BudgetID
+	} else {
+		match["budget_id"] = bson.M{"$in": query.BudgetIDs}
+	}
+
+	if query.From != nil || query.To != nil {
+		dateRange := bson.M{}
+		if query.From != nil {
+			dateRange["$gte"] = *query.From
+		}
+		if query.To != nil {
+			dateRange["$lte"] = *query.To
+		}
+		match["date"] = dateRange
+	}
+
+	return match
+}
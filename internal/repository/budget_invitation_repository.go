@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BudgetInvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.BudgetInvitation) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.BudgetInvitation, error)
+	FindPendingByToken(ctx context.Context, token string) (*domain.BudgetInvitation, error)
+	FindPendingByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.BudgetInvitation, error)
+	FindPendingByEmail(ctx context.Context, email string) ([]*domain.BudgetInvitation, error)
+	Accept(ctx context.Context, id, userID primitive.ObjectID) error
+	Revoke(ctx context.Context, id primitive.ObjectID) error
+}
+
+type budgetInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBudgetInvitationRepository(db *mongo.Database) BudgetInvitationRepository {
+	return &budgetInvitationRepository{
+		collection: db.Collection("budget_invitations"),
+	}
+}
+
+func (r *budgetInvitationRepository) Create(ctx context.Context, invitation *domain.BudgetInvitation) error {
+	invitation.CreatedAt = time.Now()
+	invitation.UpdatedAt = time.Now()
+	invitation.Status = domain.InvitationStatusPending
+
+	result, err := r.collection.InsertOne(ctx, invitation)
+	if err != nil {
+		return err
+	}
+
+	invitation.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *budgetInvitationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.BudgetInvitation, error) {
+	var invitation domain.BudgetInvitation
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPendingByToken returns the pending, unexpired invitation with the given
+// token.
+func (r *budgetInvitationRepository) FindPendingByToken(ctx context.Context, token string) (*domain.BudgetInvitation, error) {
+	var invitation domain.BudgetInvitation
+	err := r.collection.FindOne(ctx, bson.M{
+		"token":      token,
+		"status":     domain.InvitationStatusPending,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *budgetInvitationRepository) FindPendingByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.BudgetInvitation, error) {
+	return r.findPending(ctx, bson.M{"budget_id": budgetID})
+}
+
+func (r *budgetInvitationRepository) FindPendingByEmail(ctx context.Context, email string) ([]*domain.BudgetInvitation, error) {
+	return r.findPending(ctx, bson.M{"email": strings.ToLower(email)})
+}
+
+func (r *budgetInvitationRepository) findPending(ctx context.Context, filter bson.M) ([]*domain.BudgetInvitation, error) {
+	filter["status"] = domain.InvitationStatusPending
+	filter["expires_at"] = bson.M{"$gt": time.Now()}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var invitations []*domain.BudgetInvitation
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// Accept marks a pending invitation as accepted by the user. It returns
+// domain.ErrInvitationNotFound when the invitation is no longer pending.
+func (r *budgetInvitationRepository) Accept(ctx context.Context, id, userID primitive.ObjectID) error {
+	return r.resolve(ctx, id, bson.M{
+		"status":      domain.InvitationStatusAccepted,
+		"accepted_by": userID,
+	})
+}
+
+func (r *budgetInvitationRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	return r.resolve(ctx, id, bson.M{"status": domain.InvitationStatusRevoked})
+}
+
+func (r *budgetInvitationRepository) resolve(ctx context.Context, id primitive.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now()
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.InvitationStatusPending},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvitationNotFound
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/dmehra2102/budget-tracker/internal/domain"
@@ -14,11 +15,23 @@ import (
 type BudgetRepository interface {
 	Create(ctx context.Context, budget *domain.Budget) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Budget, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Budget, error)
 	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Budget, error)
 	Update(ctx context.Context, budget *domain.Budget) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
-	UpdateSpendAmount(ctx context.Context, id primitive.ObjectID, amount float64) error
+	UpdateSpendAmount(ctx context.Context, id, categoryID primitive.ObjectID, amount domain.Money) error
 	FindActiveBudgets(ctx context.Context) ([]*domain.Budget, error)
+	FindActiveForDate(ctx context.Context, userID primitive.ObjectID, date time.Time, categoryID primitive.ObjectID) (*domain.Budget, error)
+	FindPage(ctx context.Context, afterID primitive.ObjectID, limit int) ([]*domain.Budget, error)
+	SetSpentAmounts(ctx context.Context, budget *domain.Budget) error
+	FindEnded(ctx context.Context, asOf time.Time, exclude []primitive.ObjectID, limit int) ([]*domain.Budget, error)
+	Close(ctx context.Context, id primitive.ObjectID, nextID *primitive.ObjectID) (bool, error)
+	AddIncome(ctx context.Context, id primitive.ObjectID, amount domain.Money) error
+	AdjustEnvelope(ctx context.Context, id, categoryID primitive.ObjectID, amount domain.Money) error
+	RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error
+	AddMember(ctx context.Context, id primitive.ObjectID, member domain.BudgetMember) error
+	SetMemberRole(ctx context.Context, id, userID primitive.ObjectID, role domain.MemberRole) error
+	RemoveMember(ctx context.Context, id, userID primitive.ObjectID) error
 }
 
 type budgetRepository struct {
@@ -36,9 +49,13 @@ func (r *budgetRepository) Create(ctx context.Context, budget *domain.Budget) er
 	budget.UpdatedAt = time.Now()
 	budget.IsActive = true
 	budget.SpentAmount = 0
+	budget.Version = 0
 
 	result, err := r.collection.InsertOne(ctx, budget)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) && budget.PreviousBudgetID != nil {
+			return domain.ErrBudgetRolledOver
+		}
 		return err
 	}
 
@@ -58,8 +75,25 @@ func (r *budgetRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 	return &budget, nil
 }
 
+// FindByIDs returns the budgets with the given IDs, in no particular order.
+// IDs without a budget are skipped.
+func (r *budgetRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Budget, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var budgets []*domain.Budget
+	if err = cursor.All(ctx, &budgets); err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// FindByUserID returns the budgets the user owns or is a member of.
 func (r *budgetRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Budget, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID},
+	cursor, err := r.collection.Find(ctx, memberFilter(userID),
 		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
 	if err != nil {
 		return nil, err
@@ -74,20 +108,33 @@ func (r *budgetRepository) FindByUserID(ctx context.Context, userID primitive.Ob
 	return budgets, nil
 }
 
+// Update replaces the budget if it still has the version it was read at and
+// increments the version. It returns domain.ErrVersionConflict when the budget
+// has been changed since.
 func (r *budgetRepository) Update(ctx context.Context, budget *domain.Budget) error {
+	version := budget.Version
+	budget.Version++
 	budget.UpdatedAt = time.Now()
 
 	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": budget.ID},
+		bson.M{"_id": budget.ID, "version": version},
 		bson.M{"$set": budget},
 	)
+	if err != nil {
+		budget.Version = version
+		return err
+	}
 
 	if result.MatchedCount == 0 {
-		return domain.ErrBudgetNotFound
+		budget.Version = version
+		if _, err := r.FindByID(ctx, budget.ID); err != nil {
+			return err
+		}
+		return domain.ErrVersionConflict
 	}
 
-	return err
+	return nil
 }
 
 func (r *budgetRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
@@ -104,21 +151,30 @@ func (r *budgetRepository) Delete(ctx context.Context, id primitive.ObjectID) er
 	return nil
 }
 
-func (r *budgetRepository) UpdateSpendAmount(ctx context.Context, id primitive.ObjectID, amount float64) error {
+// UpdateSpendAmount adds amount to the budget's spent total and to the spent
+// amount of the category in a single update.
+func (r *budgetRepository) UpdateSpendAmount(ctx context.Context, id, categoryID primitive.ObjectID, amount domain.Money) error {
 	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		bson.M{"_id": id, "categories.category_id": categoryID},
 		bson.M{
-			"$inc": bson.M{"spend_amount": amount},
+			"$inc": bson.M{
+				"spent_amount":              amount,
+				"categories.$.spent_amount": amount,
+				"version":                   1,
+			},
 			"$set": bson.M{"updated_at": time.Now()},
 		},
 	)
+	if err != nil {
+		return err
+	}
 
 	if result.MatchedCount == 0 {
-		return domain.ErrBudgetNotFound
+		return domain.ErrCategoryNotFound
 	}
 
-	return err
+	return nil
 }
 
 func (r *budgetRepository) FindActiveBudgets(ctx context.Context) ([]*domain.Budget, error) {
@@ -138,3 +194,274 @@ func (r *budgetRepository) FindActiveBudgets(ctx context.Context) ([]*domain.Bud
 
 	return budgets, nil
 }
+
+// FindActiveForDate returns the active budget the user may add expenses to,
+// as owner or editor, whose period contains date and which tracks the given
+// category.
+func (r *budgetRepository) FindActiveForDate(ctx context.Context, userID primitive.ObjectID, date time.Time, categoryID primitive.ObjectID) (*domain.Budget, error) {
+	var budget domain.Budget
+	err := r.collection.FindOne(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"user_id": userID},
+			bson.M{"members": bson.M{"$elemMatch": bson.M{
+				"user_id": userID,
+				"role":    bson.M{"$in": bson.A{domain.MemberRoleOwner, domain.MemberRoleEditor}},
+			}}},
+		},
+		"is_active":              true,
+		"start_date":             bson.M{"$lte": date},
+		"end_date":               bson.M{"$gt": date},
+		"categories.category_id": categoryID,
+	}, options.FindOne().SetSort(bson.D{{Key: "start_date", Value: -1}})).Decode(&budget)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrBudgetNotFound
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// FindPage returns up to limit budgets with an _id greater than afterID, in
+// _id order, for jobs that walk the whole collection.
+func (r *budgetRepository) FindPage(ctx context.Context, afterID primitive.ObjectID, limit int) ([]*domain.Budget, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$gt": afterID}},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var budgets []*domain.Budget
+	if err = cursor.All(ctx, &budgets); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// SetSpentAmounts overwrites the stored spent amounts of the budget and its
+// categories with the values on budget. Each category is addressed by
+// position and guarded by ID so a concurrent category edit makes the update
+// miss instead of writing to the wrong category.
+func (r *budgetRepository) SetSpentAmounts(ctx context.Context, budget *domain.Budget) error {
+	filter := bson.M{"_id": budget.ID}
+	set := bson.M{
+		"spent_amount": budget.SpentAmount,
+		"updated_at":   time.Now(),
+	}
+	for i, category := range budget.Categories {
+		prefix := "categories." + strconv.Itoa(i)
+		filter[prefix+".category_id"] = category.CategoryID
+		set[prefix+".spent_amount"] = category.SpentAmount
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+// FindEnded returns up to limit active budgets whose period ended on or
+// before asOf, oldest first, leaving out the budgets in exclude.
+func (r *budgetRepository) FindEnded(ctx context.Context, asOf time.Time, exclude []primitive.ObjectID, limit int) ([]*domain.Budget, error) {
+	filter := bson.M{
+		"is_active": true,
+		"end_date":  bson.M{"$lte": asOf},
+	}
+	if len(exclude) > 0 {
+		filter["_id"] = bson.M{"$nin": exclude}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "end_date", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var budgets []*domain.Budget
+	if err = cursor.All(ctx, &budgets); err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// Close marks an active budget inactive and links it to the budget that
+// replaces it, if any. It reports false when the budget was already closed.
+func (r *budgetRepository) Close(ctx context.Context, id primitive.ObjectID, nextID *primitive.ObjectID) (bool, error) {
+	set := bson.M{
+		"is_active":  false,
+		"updated_at": time.Now(),
+	}
+	if nextID != nil {
+		set["next_budget_id"] = *nextID
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "is_active": true}, bson.M{
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *budgetRepository) AddIncome(ctx context.Context, id primitive.ObjectID, amount domain.Money) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"income_amount": amount, "version": 1},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+// AdjustEnvelope adds amount, which may be negative, to the money assigned to
+// the category and to the budget total.
+func (r *budgetRepository) AdjustEnvelope(ctx context.Context, id, categoryID primitive.ObjectID, amount domain.Money) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "categories.category_id": categoryID},
+		bson.M{
+			"$inc": bson.M{
+				"total_amount":        amount,
+				"categories.$.amount": amount,
+				"version":             1,
+			},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// RenameCategory updates the name kept on every budget that tracks the
+// category.
+func (r *budgetRepository) RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"categories.category_id": categoryID},
+		bson.M{
+			"$set": bson.M{"categories.$[category].name": name},
+			"$inc": bson.M{"version": 1},
+		},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []any{bson.M{"category.category_id": categoryID}},
+		}),
+	)
+	return err
+}
+
+// AddMember shares the budget with a user. It returns domain.ErrAlreadyMember
+// when the user already owns or is a member of the budget.
+func (r *budgetRepository) AddMember(ctx context.Context, id primitive.ObjectID, member domain.BudgetMember) error {
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":             id,
+			"user_id":         bson.M{"$ne": member.UserID},
+			"members.user_id": bson.M{"$ne": member.UserID},
+		},
+		bson.M{
+			"$push": bson.M{"members": member},
+			"$set":  bson.M{"updated_at": time.Now()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrAlreadyMember
+	}
+
+	return nil
+}
+
+func (r *budgetRepository) SetMemberRole(ctx context.Context, id, userID primitive.ObjectID, role domain.MemberRole) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "members.user_id": userID},
+		bson.M{
+			"$set": bson.M{
+				"members.$.role": role,
+				"updated_at":     time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *budgetRepository) RemoveMember(ctx context.Context, id, userID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "members.user_id": userID},
+		bson.M{
+			"$pull": bson.M{"members": bson.M{"user_id": userID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// memberFilter matches budgets the user owns or is a member of.
+func memberFilter(userID primitive.ObjectID) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"user_id": userID},
+		bson.M{"members.user_id": userID},
+	}}
+}
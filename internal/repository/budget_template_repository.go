@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BudgetTemplateRepository interface {
+	Create(ctx context.Context, template *domain.BudgetTemplate) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.BudgetTemplate, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.BudgetTemplate, error)
+	Update(ctx context.Context, template *domain.BudgetTemplate) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error
+}
+
+type budgetTemplateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBudgetTemplateRepository(db *mongo.Database) BudgetTemplateRepository {
+	return &budgetTemplateRepository{
+		collection: db.Collection("budget_templates"),
+	}
+}
+
+func (r *budgetTemplateRepository) Create(ctx context.Context, template *domain.BudgetTemplate) error {
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		return err
+	}
+
+	template.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *budgetTemplateRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.BudgetTemplate, error) {
+	var template domain.BudgetTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *budgetTemplateRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.BudgetTemplate, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var templates []*domain.BudgetTemplate
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *budgetTemplateRepository) Update(ctx context.Context, template *domain.BudgetTemplate) error {
+	template.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": template.ID}, bson.M{"$set": template})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+func (r *budgetTemplateRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrTemplateNotFound
+	}
+
+	return nil
+}
+
+// RenameCategory updates the name kept on every template that uses the
+// category.
+func (r *budgetTemplateRepository) RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"categories.category_id": categoryID},
+		bson.M{"$set": bson.M{"categories.$[category].name": name}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: []any{bson.M{"category.category_id": categoryID}},
+		}),
+	)
+	return err
+}
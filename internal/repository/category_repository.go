@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Category, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Category, error)
+	FindByKey(ctx context.Context, userID primitive.ObjectID, key string) (*domain.Category, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Category, error)
+	Update(ctx context.Context, category *domain.Category) error
+	SetArchived(ctx context.Context, ids []primitive.ObjectID, archived bool) error
+}
+
+type categoryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCategoryRepository(db *mongo.Database) CategoryRepository {
+	return &categoryRepository{
+		collection: db.Collection("categories"),
+	}
+}
+
+// Create stores a new category. It returns domain.ErrCategoryExists when the
+// user already has a category with the same key.
+func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	category.Key = domain.CategoryKey(category.Name)
+	category.CreatedAt = time.Now()
+	category.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, category)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrCategoryExists
+		}
+		return err
+	}
+
+	category.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *categoryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Category, error) {
+	var category domain.Category
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&category)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Category, error) {
+	return r.find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (r *categoryRepository) FindByKey(ctx context.Context, userID primitive.ObjectID, key string) (*domain.Category, error) {
+	var category domain.Category
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "key": key}).Decode(&category)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Category, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+// Update writes the editable fields of the category. It returns
+// domain.ErrCategoryExists when a rename collides with another category.
+func (r *categoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	category.Key = domain.CategoryKey(category.Name)
+	category.UpdatedAt = time.Now()
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":       category.Name,
+			"key":        category.Key,
+			"color":      category.Color,
+			"icon":       category.Icon,
+			"updated_at": category.UpdatedAt,
+		},
+	}
+	if category.ParentID != nil {
+		update["$set"].(bson.M)["parent_id"] = *category.ParentID
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": category.ID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrCategoryExists
+		}
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+func (r *categoryRepository) SetArchived(ctx context.Context, ids []primitive.ObjectID, archived bool) error {
+	_, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$set": bson.M{
+			"is_archived": archived,
+			"updated_at":  time.Now(),
+		}},
+	)
+	return err
+}
+
+func (r *categoryRepository) find(ctx context.Context, filter bson.M) ([]*domain.Category, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "key", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	categories := []*domain.Category{}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// pageCursor is the opaque position handed to clients for keyset pagination.
+// It records the sort key of the last returned document and its _id, which
+// breaks ties between documents sharing the same sort value.
+type pageCursor struct {
+	Sort  string             `json:"s"`
+	Value json.RawMessage    `json:"v"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+func encodeCursor(sort string, value any, id primitive.ObjectID) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(pageCursor{Sort: sort, Value: raw, ID: id})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor parses a cursor produced by encodeCursor for the same sort
+// field, storing the sort value into value.
+func decodeCursor(cursor, sort string, value any) (primitive.ObjectID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != sort || c.ID.IsZero() {
+		return primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(c.Value, value); err != nil {
+		return primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	return c.ID, nil
+}
+
+// seekFilter matches the documents that come after (value, id) in a listing
+// sorted by field and then _id in the given direction.
+func seekFilter(field string, value any, id primitive.ObjectID, desc bool) bson.M {
+	op := "$gt"
+	if desc {
+		op = "$lt"
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "_id": bson.M{op: id}},
+	}}
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type EnvelopeTransferRepository interface {
+	Create(ctx context.Context, transfer *domain.EnvelopeTransfer) error
+	FindByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.EnvelopeTransfer, error)
+}
+
+type envelopeTransferRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEnvelopeTransferRepository(db *mongo.Database) EnvelopeTransferRepository {
+	return &envelopeTransferRepository{
+		collection: db.Collection("envelope_transfers"),
+	}
+}
+
+func (r *envelopeTransferRepository) Create(ctx context.Context, transfer *domain.EnvelopeTransfer) error {
+	transfer.CreatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, transfer)
+	if err != nil {
+		return err
+	}
+
+	transfer.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *envelopeTransferRepository) FindByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.EnvelopeTransfer, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"budget_id": budgetID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var transfers []*domain.EnvelopeTransfer
+	if err := cursor.All(ctx, &transfers); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
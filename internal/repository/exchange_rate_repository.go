@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rates []*domain.ExchangeRate) (int64, error)
+	FindLatest(ctx context.Context, base, quote domain.Currency, date time.Time) (*domain.ExchangeRate, error)
+}
+
+type exchangeRateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewExchangeRateRepository(db *mongo.Database) ExchangeRateRepository {
+	return &exchangeRateRepository{
+		collection: db.Collection("exchange_rates"),
+	}
+}
+
+// Upsert stores rates keyed by base, quote and date, replacing any rate
+// already loaded for the same day. It returns the number of rates inserted or
+// changed.
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rates []*domain.ExchangeRate) (int64, error) {
+	if len(rates) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(rates))
+	for _, rate := range rates {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"base": rate.Base, "quote": rate.Quote, "date": rate.Date}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"rate":       rate.Rate,
+				"source":     rate.Source,
+				"updated_at": now,
+			}}).
+			SetUpsert(true))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return result.UpsertedCount + result.ModifiedCount, nil
+}
+
+// FindLatest returns the most recent rate published on or before date.
+func (r *exchangeRateRepository) FindLatest(ctx context.Context, base, quote domain.Currency, date time.Time) (*domain.ExchangeRate, error) {
+	var rate domain.ExchangeRate
+	err := r.collection.FindOne(ctx,
+		bson.M{"base": base, "quote": quote, "date": bson.M{"$lte": date}},
+		options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}),
+	).Decode(&rate)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrRateNotFound
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/dmehra2102/budget-tracker/internal/domain"
@@ -16,8 +17,11 @@ type ExpenseRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Expense, error)
 	FindByBudgetID(ctx context.Context, budgetID primitive.ObjectID) ([]*domain.Expense, error)
 	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Expense, error)
+	Query(ctx context.Context, query *domain.ExpenseQuery) (*domain.ExpensePage, error)
+	SumSpendByBudget(ctx context.Context, budgetIDs []primitive.ObjectID) ([]domain.CategorySpend, error)
 	Update(ctx context.Context, expense *domain.Expense) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error
 }
 
 type expenseRepository struct {
@@ -33,9 +37,13 @@ func NewExpenseRepository(db *mongo.Database) ExpenseRepository {
 func (r *expenseRepository) Create(ctx context.Context, expense *domain.Expense) error {
 	expense.CreatedAt = time.Now()
 	expense.UpdatedAt = time.Now()
+	expense.Version = 0
 
 	result, err := r.collection.InsertOne(ctx, expense)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) && expense.RecurringExpenseID != nil {
+			return domain.ErrOccurrenceExists
+		}
 		return err
 	}
 
@@ -83,17 +91,189 @@ func (r *expenseRepository) FindByUserID(ctx context.Context, userID primitive.O
 	return expenses, nil
 }
 
+// Query returns one page of expenses. Filters lead with budget_id followed by
+// date so that the compound index on those fields is used.
+func (r *expenseRepository) Query(ctx context.Context, query *domain.ExpenseQuery) (*domain.ExpensePage, error) {
+	conditions := bson.A{}
+	if query.BudgetID != nil {
+		conditions = append(conditions, bson.M{"budget_id": *query.BudgetID})
+	} else {
+		conditions = append(conditions, bson.M{"budget_id": bson.M{"$in": query.BudgetIDs}})
+	}
+
+	if query.From != nil || query.To != nil {
+		dateRange := bson.M{}
+		if query.From != nil {
+			dateRange["$gte"] = *query.From
+		}
+		if query.To != nil {
+			dateRange["$lte"] = *query.To
+		}
+		conditions = append(conditions, bson.M{"date": dateRange})
+	}
+
+	if len(query.Categories) > 0 {
+		conditions = append(conditions, bson.M{"category_id": bson.M{"$in": query.Categories}})
+	}
+
+	if query.MinAmount != nil || query.MaxAmount != nil {
+		amountRange := bson.M{}
+		if query.MinAmount != nil {
+			amountRange["$gte"] = *query.MinAmount
+		}
+		if query.MaxAmount != nil {
+			amountRange["$lte"] = *query.MaxAmount
+		}
+		conditions = append(conditions, bson.M{"amount": amountRange})
+	}
+
+	if query.Search != "" {
+		conditions = append(conditions, bson.M{"description": primitive.Regex{
+			Pattern: regexp.QuoteMeta(query.Search),
+			Options: "i",
+		}})
+	}
+
+	sortField := string(query.SortBy)
+	if sortField == "" {
+		sortField = string(domain.ExpenseSortDate)
+	}
+
+	if query.Cursor != "" {
+		var (
+			value any
+			id    primitive.ObjectID
+			err   error
+		)
+		switch domain.ExpenseSortField(sortField) {
+		case domain.ExpenseSortAmount:
+			var amount domain.Money
+			id, err = decodeCursor(query.Cursor, sortField, &amount)
+			value = amount
+		default:
+			var date time.Time
+			id, err = decodeCursor(query.Cursor, sortField, &date)
+			value = date
+		}
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, seekFilter(sortField, value, id, query.SortDesc))
+	}
+
+	direction := 1
+	if query.SortDesc {
+		direction = -1
+	}
+
+	limit := pageSize(query.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(limit + 1))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	expenses := []*domain.Expense{}
+	if err := cursor.All(ctx, &expenses); err != nil {
+		return nil, err
+	}
+
+	page := &domain.ExpensePage{Items: expenses}
+	if len(expenses) > limit {
+		page.Items = expenses[:limit]
+		last := page.Items[limit-1]
+
+		var value any = last.Date
+		if domain.ExpenseSortField(sortField) == domain.ExpenseSortAmount {
+			value = last.Amount
+		}
+
+		page.NextCursor, err = encodeCursor(sortField, value, last.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// SumSpendByBudget totals the expenses of the given budgets per category.
+func (r *expenseRepository) SumSpendByBudget(ctx context.Context, budgetIDs []primitive.ObjectID) ([]domain.CategorySpend, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"budget_id": bson.M{"$in": budgetIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"budget_id": "$budget_id", "category_id": "$category_id"},
+			"total": bson.M{"$sum": "$amount"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"budget_id":   "$_id.budget_id",
+			"category_id": "$_id.category_id",
+			"total":       1,
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var spend []domain.CategorySpend
+	if err := cursor.All(ctx, &spend); err != nil {
+		return nil, err
+	}
+	return spend, nil
+}
+
+// Update replaces the expense if it still has the version it was read at and
+// increments the version. It returns domain.ErrVersionConflict when the
+// expense has been changed since.
 func (r *expenseRepository) Update(ctx context.Context, expense *domain.Expense) error {
+	version := expense.Version
+	expense.Version++
 	expense.UpdatedAt = time.Now()
 
-	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": expense.ID}, bson.M{
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": expense.ID, "version": version}, bson.M{
 		"$set": expense,
 	})
+	if err != nil {
+		expense.Version = version
+		return err
+	}
 
-	return err
+	if result.MatchedCount == 0 {
+		expense.Version = version
+		if _, err := r.FindByID(ctx, expense.ID); err != nil {
+			return err
+		}
+		return domain.ErrVersionConflict
+	}
+
+	return nil
 }
 
 func (r *expenseRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrExpenseNotFound
+	}
+
+	return nil
+}
+
+func (r *expenseRepository) RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"category_id": categoryID}, bson.M{
+		"$set": bson.M{"category": name},
+		"$inc": bson.M{"version": 1},
+	})
 	return err
 }
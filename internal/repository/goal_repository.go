@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type GoalRepository interface {
+	Create(ctx context.Context, goal *domain.Goal) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Goal, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Goal, error)
+	FindOpen(ctx context.Context, asOf time.Time) ([]*domain.Goal, error)
+	Update(ctx context.Context, goal *domain.Goal) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	AddSaved(ctx context.Context, id primitive.ObjectID, amount domain.Money) error
+	ClaimBehindReminder(ctx context.Context, id primitive.ObjectID, previous *time.Time, at time.Time) (bool, error)
+	ReleaseBehindReminder(ctx context.Context, id primitive.ObjectID, at time.Time, previous *time.Time) error
+	CreateContribution(ctx context.Context, contribution *domain.GoalContribution) error
+	FindContribution(ctx context.Context, id primitive.ObjectID) (*domain.GoalContribution, error)
+	FindContributions(ctx context.Context, goalID primitive.ObjectID) ([]*domain.GoalContribution, error)
+	DeleteContribution(ctx context.Context, id primitive.ObjectID) error
+	DeleteContributions(ctx context.Context, goalID primitive.ObjectID) error
+	RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error
+}
+
+type goalRepository struct {
+	collection             *mongo.Collection
+	contributionCollection *mongo.Collection
+}
+
+func NewGoalRepository(db *mongo.Database) GoalRepository {
+	return &goalRepository{
+		collection:             db.Collection("goals"),
+		contributionCollection: db.Collection("goal_contributions"),
+	}
+}
+
+func (r *goalRepository) Create(ctx context.Context, goal *domain.Goal) error {
+	goal.CreatedAt = time.Now()
+	goal.UpdatedAt = time.Now()
+	goal.SavedAmount = 0
+
+	result, err := r.collection.InsertOne(ctx, goal)
+	if err != nil {
+		return err
+	}
+
+	goal.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *goalRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Goal, error) {
+	var goal domain.Goal
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&goal)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrGoalNotFound
+		}
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r *goalRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.Goal, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "target_date", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var goals []*domain.Goal
+	if err := cursor.All(ctx, &goals); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// FindOpen returns the goals that have not reached their target and whose
+// target date is still ahead of asOf.
+func (r *goalRepository) FindOpen(ctx context.Context, asOf time.Time) ([]*domain.Goal, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"target_date": bson.M{"$gt": asOf},
+		"$expr":       bson.M{"$lt": bson.A{"$saved_amount", "$target_amount"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var goals []*domain.Goal
+	if err := cursor.All(ctx, &goals); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+func (r *goalRepository) Update(ctx context.Context, goal *domain.Goal) error {
+	goal.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": goal.ID}, bson.M{
+		"$set": bson.M{
+			"name":          goal.Name,
+			"category_id":   goal.CategoryID,
+			"category":      goal.Category,
+			"target_amount": goal.TargetAmount,
+			"target_date":   goal.TargetDate,
+			"updated_at":    goal.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrGoalNotFound
+	}
+
+	return nil
+}
+
+func (r *goalRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrGoalNotFound
+	}
+
+	return nil
+}
+
+// AddSaved adds amount, which may be negative, to the goal's saved amount.
+func (r *goalRepository) AddSaved(ctx context.Context, id primitive.ObjectID, amount domain.Money) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"saved_amount": amount},
+			"$set": bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrGoalNotFound
+	}
+
+	return nil
+}
+
+// ClaimBehindReminder records a reminder sent at the given time, provided the
+// goal's last reminder is still previous. It reports false when another run
+// has claimed the reminder first.
+func (r *goalRepository) ClaimBehindReminder(ctx context.Context, id primitive.ObjectID, previous *time.Time, at time.Time) (bool, error) {
+	filter := bson.M{"_id": id, "behind_notified_at": nil}
+	if previous != nil {
+		filter["behind_notified_at"] = *previous
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"behind_notified_at": at},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ReleaseBehindReminder restores the previous reminder time after the
+// reminder claimed at the given time could not be sent.
+func (r *goalRepository) ReleaseBehindReminder(ctx context.Context, id primitive.ObjectID, at time.Time, previous *time.Time) error {
+	update := bson.M{"$unset": bson.M{"behind_notified_at": ""}}
+	if previous != nil {
+		update = bson.M{"$set": bson.M{"behind_notified_at": *previous}}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "behind_notified_at": at}, update)
+	return err
+}
+
+func (r *goalRepository) CreateContribution(ctx context.Context, contribution *domain.GoalContribution) error {
+	contribution.CreatedAt = time.Now()
+
+	result, err := r.contributionCollection.InsertOne(ctx, contribution)
+	if err != nil {
+		return err
+	}
+
+	contribution.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *goalRepository) FindContribution(ctx context.Context, id primitive.ObjectID) (*domain.GoalContribution, error) {
+	var contribution domain.GoalContribution
+	err := r.contributionCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&contribution)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrContributionNotFound
+		}
+		return nil, err
+	}
+	return &contribution, nil
+}
+
+func (r *goalRepository) FindContributions(ctx context.Context, goalID primitive.ObjectID) ([]*domain.GoalContribution, error) {
+	cursor, err := r.contributionCollection.Find(ctx, bson.M{"goal_id": goalID},
+		options.Find().SetSort(bson.D{{Key: "date", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var contributions []*domain.GoalContribution
+	if err := cursor.All(ctx, &contributions); err != nil {
+		return nil, err
+	}
+	return contributions, nil
+}
+
+func (r *goalRepository) DeleteContribution(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.contributionCollection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrContributionNotFound
+	}
+
+	return nil
+}
+
+func (r *goalRepository) DeleteContributions(ctx context.Context, goalID primitive.ObjectID) error {
+	_, err := r.contributionCollection.DeleteMany(ctx, bson.M{"goal_id": goalID})
+	return err
+}
+
+func (r *goalRepository) RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"category_id": categoryID}, bson.M{"$set": bson.M{"category": name}})
+	return err
+}
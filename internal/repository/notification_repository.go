@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationRepository reads and updates a user's inbox: the in-app
+// notifications in the alert_notifications collection.
+type NotificationRepository interface {
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.AlertNotification, error)
+	Query(ctx context.Context, query *domain.NotificationQuery) (*domain.NotificationPage, error)
+	CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	SetRead(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, read bool) (int64, error)
+}
+
+type notificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(db *mongo.Database) NotificationRepository {
+	return &notificationRepository{
+		collection: db.Collection("alert_notifications"),
+	}
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.AlertNotification, error) {
+	var notification domain.AlertNotification
+	err := r.collection.FindOne(ctx, bson.M{
+		"_id":     id,
+		"channel": domain.NotificationChannelInApp,
+	}).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// Query returns one page of the user's inbox, newest first.
+func (r *notificationRepository) Query(ctx context.Context, query *domain.NotificationQuery) (*domain.NotificationPage, error) {
+	conditions := bson.A{inboxFilter(query.UserID)}
+	if query.UnreadOnly {
+		conditions = append(conditions, bson.M{"read_at": nil})
+	}
+
+	if query.Cursor != "" {
+		var sentAt time.Time
+		id, err := decodeCursor(query.Cursor, "sent_at", &sentAt)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, seekFilter("sent_at", sentAt, id, true))
+	}
+
+	limit := pageSize(query.Limit)
+	opts := options.Find().
+		SetSort(bson.D{{Key: "sent_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit + 1))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []*domain.AlertNotification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	page := &domain.NotificationPage{Items: notifications}
+	if len(notifications) > limit {
+		page.Items = notifications[:limit]
+		last := page.Items[limit-1]
+
+		page.NextCursor, err = encodeCursor("sent_at", last.SentAt, last.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	filter := inboxFilter(userID)
+	filter["read_at"] = nil
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// SetRead marks the given notifications of the user, or all of them when ids
+// is nil, as read or unread. Notifications already read keep the time they
+// were first read. It returns the number of notifications changed.
+func (r *notificationRepository) SetRead(ctx context.Context, userID primitive.ObjectID, ids []primitive.ObjectID, read bool) (int64, error) {
+	filter := inboxFilter(userID)
+	if ids != nil {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	var update bson.M
+	if read {
+		filter["read_at"] = nil
+		update = bson.M{"$set": bson.M{"read_at": time.Now()}}
+	} else {
+		filter["read_at"] = bson.M{"$ne": nil}
+		update = bson.M{"$unset": bson.M{"read_at": ""}}
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func inboxFilter(userID primitive.ObjectID) bson.M {
+	return bson.M{
+		"user_id": userID,
+		"channel": domain.NotificationChannelInApp,
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OutboxRepository interface {
+	Append(ctx context.Context, event *domain.OutboxEvent) error
+	Claim(ctx context.Context, lease time.Duration, maxAttempts int) (*domain.OutboxEvent, error)
+	MarkProcessed(ctx context.Context, id primitive.ObjectID) error
+	MarkFailed(ctx context.Context, id primitive.ObjectID, cause error, dead bool) error
+	MarkExhausted(ctx context.Context, maxAttempts int) (int64, error)
+}
+
+type outboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOutboxRepository(db *mongo.Database) OutboxRepository {
+	return &outboxRepository{
+		collection: db.Collection("outbox_events"),
+	}
+}
+
+// Append writes a new event. Call it with the context of the unit of work
+// making the change, so the event commits or aborts with it.
+func (r *outboxRepository) Append(ctx context.Context, event *domain.OutboxEvent) error {
+	event.ID = primitive.NewObjectID()
+	event.OccurredAt = time.Now()
+	event.Attempts = 0
+	event.LockedUntil = nil
+	event.ProcessedAt = nil
+
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+// Claim locks the oldest pending event for lease and counts the attempt. It
+// returns nil when no event is pending. Events that have already been
+// attempted maxAttempts times are left alone.
+func (r *outboxRepository) Claim(ctx context.Context, lease time.Duration, maxAttempts int) (*domain.OutboxEvent, error) {
+	now := time.Now()
+	filter := bson.M{
+		"processed_at": nil,
+		"attempts":     bson.M{"$lt": maxAttempts},
+		"$or": bson.A{
+			bson.M{"locked_until": nil},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"locked_until": now.Add(lease)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var event domain.OutboxEvent
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *outboxRepository) MarkProcessed(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   bson.M{"processed_at": time.Now()},
+			"$unset": bson.M{"locked_until": "", "last_error": ""},
+		},
+	)
+	return err
+}
+
+// MarkFailed records why handling the event failed and releases its lock so
+// it can be claimed again, or marks it dead when it has no attempts left.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id primitive.ObjectID, cause error, dead bool) error {
+	set := bson.M{"last_error": cause.Error()}
+	if dead {
+		set["dead_at"] = time.Now()
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$set":   set,
+			"$unset": bson.M{"locked_until": ""},
+		},
+	)
+	return err
+}
+
+// MarkExhausted marks dead the events that used up their attempts without
+// being marked failed, because the poller handling them stopped before its
+// lock expired. It returns how many it marked.
+func (r *outboxRepository) MarkExhausted(ctx context.Context, maxAttempts int) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"processed_at": nil,
+			"dead_at":      nil,
+			"attempts":     bson.M{"$gte": maxAttempts},
+			"locked_until": bson.M{"$lte": time.Now()},
+		},
+		bson.M{
+			"$set":   bson.M{"dead_at": time.Now(), "last_error": "lock expired on the last attempt"},
+			"$unset": bson.M{"locked_until": ""},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
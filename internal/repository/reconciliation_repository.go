@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReconciliationRepository interface {
+	Create(ctx context.Context, report *domain.ReconciliationReport) error
+	FindLatest(ctx context.Context) (*domain.ReconciliationReport, error)
+	FindRecent(ctx context.Context, limit int) ([]*domain.ReconciliationReport, error)
+}
+
+type reconciliationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewReconciliationRepository(db *mongo.Database) ReconciliationRepository {
+	return &reconciliationRepository{
+		collection: db.Collection("reconciliation_reports"),
+	}
+}
+
+func (r *reconciliationRepository) Create(ctx context.Context, report *domain.ReconciliationReport) error {
+	result, err := r.collection.InsertOne(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	report.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindLatest returns the most recent report, or nil if none has been stored.
+func (r *reconciliationRepository) FindLatest(ctx context.Context) (*domain.ReconciliationReport, error) {
+	var report domain.ReconciliationReport
+	err := r.collection.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}})).Decode(&report)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *reconciliationRepository) FindRecent(ctx context.Context, limit int) ([]*domain.ReconciliationReport, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []*domain.ReconciliationReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
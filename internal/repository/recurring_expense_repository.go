@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RecurringExpenseRepository interface {
+	Create(ctx context.Context, recurring *domain.RecurringExpense) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.RecurringExpense, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.RecurringExpense, error)
+	FindDue(ctx context.Context, asOf time.Time) ([]*domain.RecurringExpense, error)
+	Update(ctx context.Context, recurring *domain.RecurringExpense) error
+	UpdateSchedule(ctx context.Context, id primitive.ObjectID, occurrenceCount int, next *time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error
+}
+
+type recurringExpenseRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRecurringExpenseRepository(db *mongo.Database) RecurringExpenseRepository {
+	return &recurringExpenseRepository{
+		collection: db.Collection("recurring_expenses"),
+	}
+}
+
+func (r *recurringExpenseRepository) Create(ctx context.Context, recurring *domain.RecurringExpense) error {
+	recurring.CreatedAt = time.Now()
+	recurring.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, recurring)
+	if err != nil {
+		return err
+	}
+
+	recurring.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *recurringExpenseRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.RecurringExpense, error) {
+	var recurring domain.RecurringExpense
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&recurring)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrRecurringNotFound
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *recurringExpenseRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.RecurringExpense, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var recurring []*domain.RecurringExpense
+	if err := cursor.All(ctx, &recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (r *recurringExpenseRepository) FindDue(ctx context.Context, asOf time.Time) ([]*domain.RecurringExpense, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"is_active":       true,
+		"next_occurrence": bson.M{"$lte": asOf},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var recurring []*domain.RecurringExpense
+	if err := cursor.All(ctx, &recurring); err != nil {
+		return nil, err
+	}
+	return recurring, nil
+}
+
+func (r *recurringExpenseRepository) Update(ctx context.Context, recurring *domain.RecurringExpense) error {
+	recurring.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": recurring.ID},
+		recurringUpdate(recurring),
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrRecurringNotFound
+	}
+
+	return nil
+}
+
+// recurringUpdate replaces the stored fields with those of recurring. An
+// ended schedule has no next occurrence, which omitempty leaves out of the
+// $set, so the stored one is unset explicitly.
+func recurringUpdate(recurring *domain.RecurringExpense) bson.M {
+	update := bson.M{"$set": recurring}
+	if recurring.NextOccurrence == nil {
+		update["$unset"] = bson.M{"next_occurrence": ""}
+	}
+	return update
+}
+
+func (r *recurringExpenseRepository) UpdateSchedule(ctx context.Context, id primitive.ObjectID, occurrenceCount int, next *time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"occurrence_count": occurrenceCount,
+			"updated_at":       time.Now(),
+		},
+	}
+	if next != nil {
+		update["$set"].(bson.M)["next_occurrence"] = *next
+	} else {
+		update["$unset"] = bson.M{"next_occurrence": ""}
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *recurringExpenseRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrRecurringNotFound
+	}
+
+	return nil
+}
+
+func (r *recurringExpenseRepository) RenameCategory(ctx context.Context, categoryID primitive.ObjectID, name string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"category_id": categoryID}, bson.M{"$set": bson.M{"category": name}})
+	return err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRecurringUpdate(t *testing.T) {
+	start := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	ended := start.AddDate(0, 2, 0)
+
+	tests := []struct {
+		name      string
+		until     *time.Time
+		wantNext  bool
+		wantUnset bool
+	}{
+		{name: "open schedule keeps its next occurrence", wantNext: true},
+		{name: "edit that ends the schedule unsets it", until: &ended, wantUnset: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurring := &domain.RecurringExpense{
+				ID:              primitive.NewObjectID(),
+				Rule:            domain.RecurrenceRule{Frequency: domain.RecurrenceMonthly, Until: tt.until},
+				StartDate:       start,
+				OccurrenceCount: 3,
+			}
+			recurring.NextOccurrence = recurring.Rule.Occurrence(recurring.StartDate, recurring.OccurrenceCount)
+
+			data, err := bson.Marshal(recurringUpdate(recurring))
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			var update struct {
+				Set   bson.M `bson:"$set"`
+				Unset bson.M `bson:"$unset"`
+			}
+			if err := bson.Unmarshal(data, &update); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			if _, ok := update.Set["next_occurrence"]; ok != tt.wantNext {
+				t.Errorf("$set next_occurrence present = %v, want %v", ok, tt.wantNext)
+			}
+			if _, ok := update.Unset["next_occurrence"]; ok != tt.wantUnset {
+				t.Errorf("$unset next_occurrence present = %v, want %v", ok, tt.wantUnset)
+			}
+		})
+	}
+}
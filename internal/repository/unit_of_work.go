@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// UnitOfWork runs a group of repository calls as one MongoDB transaction.
+// Repositories take part without any extra wiring: the context passed to fn
+// carries the session, and every collection operation made with that context
+// joins the transaction.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type mongoUnitOfWork struct {
+	client *mongo.Client
+}
+
+func NewUnitOfWork(db *mongo.Database) UnitOfWork {
+	return &mongoUnitOfWork{
+		client: db.Client(),
+	}
+}
+
+// Do commits when fn returns nil and aborts otherwise. Transient transaction
+// errors are retried by the driver, so fn must be safe to run more than once.
+// Calls made while a transaction is already open simply join it.
+func (u *mongoUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	session, err := u.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	txnOptions := options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority())
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	}, txnOptions)
+	return err
+}
@@ -13,6 +13,7 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.User, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
 	UpdateResetToken(ctx context.Context, email, token string, expiry time.Time) error
@@ -55,6 +56,22 @@ func (r *userRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*
 	return &user, nil
 }
 
+// FindByIDs returns the users with the given IDs, in no particular order. IDs
+// without a user are skipped.
+func (r *userRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
 	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/dmehra2102/budget-tracker/internal/domain"
@@ -14,52 +17,63 @@ type AlertService interface {
 	CreateAlert(ctx context.Context, userID primitive.ObjectID, req *domain.CreateAlertRequest) (*domain.Alert, error)
 	GetAlert(ctx context.Context, userID, alertID primitive.ObjectID) (*domain.Alert, error)
 	GetUserAlerts(ctx context.Context, userID primitive.ObjectID) ([]*domain.Alert, error)
+	GetBudgetAlerts(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.Alert, error)
 	UpdateAlert(ctx context.Context, userID, alertID primitive.ObjectID, req *domain.CreateAlertRequest) (*domain.Alert, error)
+	SetAlertEnabled(ctx context.Context, userID, alertID primitive.ObjectID, enabled bool) (*domain.Alert, error)
 	DeleteAlert(ctx context.Context, userID, alertID primitive.ObjectID) error
 	CheckAndSendAlerts(ctx context.Context) error
+	CheckBudgetAlerts(ctx context.Context, budgetID primitive.ObjectID) error
 }
 
 type alertService struct {
-	alertRepo    repository.AlertRepository
-	budgetRepo   repository.BudgetRepository
-	userRepo     repository.UserRepository
-	emailService EmailService
+	alertRepo   repository.AlertRepository
+	budgetRepo  repository.BudgetRepository
+	userRepo    repository.UserRepository
+	notifiers   map[domain.NotificationChannel]Notifier
+	concurrency int
 }
 
+// NewAlertService evaluates up to concurrency alerts at a time.
 func NewAlertService(
 	alertRepo repository.AlertRepository,
 	budgetRepo repository.BudgetRepository,
 	userRepo repository.UserRepository,
-	emailService EmailService,
+	notifiers map[domain.NotificationChannel]Notifier,
+	concurrency int,
 ) AlertService {
 	return &alertService{
-		alertRepo:    alertRepo,
-		budgetRepo:   budgetRepo,
-		userRepo:     userRepo,
-		emailService: emailService,
+		alertRepo:   alertRepo,
+		budgetRepo:  budgetRepo,
+		userRepo:    userRepo,
+		notifiers:   notifiers,
+		concurrency: max(concurrency, 1),
 	}
 }
 
 func (s *alertService) CreateAlert(ctx context.Context, userID primitive.ObjectID, req *domain.CreateAlertRequest) (*domain.Alert, error) {
-	budgetID, err := primitive.ObjectIDFromHex(req.BudgetID)
+	budgetID, categoryID, err := s.resolveScope(ctx, userID, req)
 	if err != nil {
-		return nil, domain.ErrInvalidInput
+		return nil, err
 	}
 
-	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	tiers, err := domain.NormalizeAlertTiers(req.Type, req.Threshold, req.Tiers)
 	if err != nil {
 		return nil, err
 	}
 
-	if budget.UserID != userID {
-		return nil, domain.ErrUnauthorized
+	channels, err := alertChannels(req.Channels, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	alert := &domain.Alert{
-		UserID:    userID,
-		BudgetID:  budgetID,
-		Type:      req.Type,
-		Threshold: req.Threshold,
+		UserID:     userID,
+		BudgetID:   budgetID,
+		CategoryID: categoryID,
+		Type:       req.Type,
+		Tiers:      tiers,
+		Channels:   channels,
+		FiredTiers: []domain.Money{},
 	}
 
 	if err := s.alertRepo.Create(ctx, alert); err != nil {
@@ -86,18 +100,70 @@ func (s *alertService) GetUserAlerts(ctx context.Context, userID primitive.Objec
 	return s.alertRepo.FindByUserID(ctx, userID)
 }
 
+func (s *alertService) GetBudgetAlerts(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.Alert, error) {
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := budget.Authorize(userID, domain.MemberRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.alertRepo.FindByBudgetID(ctx, budgetID)
+}
+
 func (s *alertService) UpdateAlert(ctx context.Context, userID, alertID primitive.ObjectID, req *domain.CreateAlertRequest) (*domain.Alert, error) {
 	alert, err := s.GetAlert(ctx, userID, alertID)
 	if err != nil {
 		return nil, err
 	}
 
+	budgetID, categoryID, err := s.resolveScope(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := domain.NormalizeAlertTiers(req.Type, req.Threshold, req.Tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := alertChannels(req.Channels, alert.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	resetTiers := budgetID != alert.BudgetID || !sameObjectID(categoryID, alert.CategoryID) ||
+		req.Type != alert.Type || !slices.Equal(tiers, alert.Tiers)
+	if resetTiers {
+		alert.FiredTiers = []domain.Money{}
+		alert.PeriodStart = nil
+	}
+
+	alert.BudgetID = budgetID
+	alert.CategoryID = categoryID
 	alert.Type = req.Type
-	alert.Threshold = req.Threshold
+	alert.Tiers = tiers
+	alert.Channels = channels
+
+	if err := s.alertRepo.Update(ctx, alert, resetTiers); err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (s *alertService) SetAlertEnabled(ctx context.Context, userID, alertID primitive.ObjectID, enabled bool) (*domain.Alert, error) {
+	alert, err := s.GetAlert(ctx, userID, alertID)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.alertRepo.Update(ctx, alert); err != nil {
+	if err := s.alertRepo.SetEnabled(ctx, alertID, enabled); err != nil {
 		return nil, err
 	}
+	alert.IsEnabled = enabled
 
 	return alert, nil
 }
@@ -115,62 +181,328 @@ func (s *alertService) DeleteAlert(ctx context.Context, userID, alertID primitiv
 	return s.alertRepo.Delete(ctx, alertID)
 }
 
+// resolveScope parses the budget and category IDs from an alert request and
+// makes sure the budget is shared with the caller and tracks the category.
+func (s *alertService) resolveScope(ctx context.Context, userID primitive.ObjectID, req *domain.CreateAlertRequest) (primitive.ObjectID, *primitive.ObjectID, error) {
+	budgetID, err := primitive.ObjectIDFromHex(req.BudgetID)
+	if err != nil {
+		return primitive.NilObjectID, nil, domain.ErrInvalidInput
+	}
+
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return primitive.NilObjectID, nil, err
+	}
+
+	if err := budget.Authorize(userID, domain.MemberRoleViewer); err != nil {
+		return primitive.NilObjectID, nil, err
+	}
+
+	if req.CategoryID == "" {
+		return budgetID, nil, nil
+	}
+
+	categoryID, err := primitive.ObjectIDFromHex(req.CategoryID)
+	if err != nil {
+		return primitive.NilObjectID, nil, domain.ErrInvalidInput
+	}
+	if budget.FindCategory(categoryID) == nil {
+		return primitive.NilObjectID, nil, domain.ErrCategoryNotFound
+	}
+
+	return budgetID, &categoryID, nil
+}
+
+// CheckAndSendAlerts evaluates every enabled alert. It is the periodic safety
+// net behind CheckBudgetAlerts.
 func (s *alertService) CheckAndSendAlerts(ctx context.Context) error {
 	alerts, err := s.alertRepo.FindActiveAlerts(ctx)
 	if err != nil {
 		return err
 	}
 
+	return s.evaluate(ctx, alerts)
+}
+
+// CheckBudgetAlerts evaluates the enabled alerts of one budget, after its
+// spending has changed.
+func (s *alertService) CheckBudgetAlerts(ctx context.Context, budgetID primitive.ObjectID) error {
+	alerts, err := s.alertRepo.FindActiveByBudgetID(ctx, budgetID)
+	if err != nil {
+		return err
+	}
+
+	return s.evaluate(ctx, alerts)
+}
+
+// evaluate loads the budgets and users of the alerts in two queries and
+// checks the alerts on a pool of goroutines. It stops handing out alerts once
+// ctx is done. Failed alerts do not stop the others; the error reports how
+// many failed.
+func (s *alertService) evaluate(ctx context.Context, alerts []*domain.Alert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	budgets, err := s.loadBudgets(ctx, alerts)
+	if err != nil {
+		return err
+	}
+
+	users, err := s.loadUsers(ctx, alerts)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failed   int
+		firstErr error
+	)
+
+	jobs := make(chan *domain.Alert)
+	for range min(s.concurrency, len(alerts)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for alert := range jobs {
+				err := s.checkAlert(ctx, alert, budgets[alert.BudgetID], users[alert.UserID])
+				if err != nil {
+					mu.Lock()
+					failed++
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+dispatch:
 	for _, alert := range alerts {
-		budget, err := s.budgetRepo.FindByID(ctx, alert.BudgetID)
-		if err != nil {
-			continue
+		select {
+		case jobs <- alert:
+		case <-ctx.Done():
+			break dispatch
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		percentageSpent := (budget.SpentAmount / budget.TotalAmount) * 100
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("alert check stopped early: %w", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d alerts failed: %w", failed, len(alerts), firstErr)
+	}
+	return nil
+}
 
-		shouldTrigger := false
-		switch alert.Type {
-		case domain.AlertTypePercentage:
-			shouldTrigger = percentageSpent >= alert.Threshold
-		case domain.AlertTypeAmount:
-			shouldTrigger = budget.SpentAmount >= alert.Threshold
+func (s *alertService) loadBudgets(ctx context.Context, alerts []*domain.Alert) (map[primitive.ObjectID]*domain.Budget, error) {
+	ids := make([]primitive.ObjectID, 0, len(alerts))
+	for _, alert := range alerts {
+		ids = append(ids, alert.BudgetID)
+	}
+
+	budgets, err := s.budgetRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*domain.Budget, len(budgets))
+	for _, budget := range budgets {
+		byID[budget.ID] = budget
+	}
+	return byID, nil
+}
+
+func (s *alertService) loadUsers(ctx context.Context, alerts []*domain.Alert) (map[primitive.ObjectID]*domain.User, error) {
+	ids := make([]primitive.ObjectID, 0, len(alerts))
+	for _, alert := range alerts {
+		ids = append(ids, alert.UserID)
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*domain.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	return byID, nil
+}
+
+// checkAlert fires the tiers the alert has newly reached in the current
+// period of its budget. Tiers reached together are sent as one notification
+// for the highest of them, which is claimed in the database before anything is
+// sent. Alerts whose budget or user is gone, or whose budget is closed, are
+// skipped.
+func (s *alertService) checkAlert(ctx context.Context, alert *domain.Alert, budget *domain.Budget, user *domain.User) error {
+	if budget == nil || user == nil || !budget.IsActive {
+		return nil
+	}
+
+	// The alert's owner may since have been removed from the budget.
+	if err := budget.Authorize(alert.UserID, domain.MemberRoleViewer); err != nil {
+		return nil
+	}
+
+	spent, total := budget.SpentAmount, budget.TotalAmount
+	categoryName := ""
+	if alert.CategoryID != nil {
+		category := budget.FindCategory(*alert.CategoryID)
+		if category == nil {
+			return nil
 		}
+		spent, total = category.SpentAmount, category.Amount
+		categoryName = category.Name
+	}
 
-		if shouldTrigger {
-			if alert.LastSentAt != nil && time.Since(*alert.LastSentAt) < 24*time.Hour {
-				continue
-			}
+	if alert.PeriodStart == nil || !alert.PeriodStart.Equal(budget.StartDate) {
+		alert.FiredTiers = nil
+	}
 
-			user, err := s.userRepo.FindByID(ctx, alert.UserID)
-			if err != nil {
-				continue
-			}
+	var newTiers []domain.Money
+	for _, tier := range alert.ReachedTiers(spent, total) {
+		if !alert.HasFired(tier) {
+			newTiers = append(newTiers, tier)
+		}
+	}
+	if len(newTiers) == 0 {
+		return nil
+	}
+	tier := newTiers[len(newTiers)-1]
 
-			if err := s.emailService.SendBudgetAlertEmail(
-				ctx,
-				user.Email,
-				user.FirstName,
-				budget.Name,
-				percentageSpent,
-			); err != nil {
-				continue
-			}
+	claimed, err := s.alertRepo.ClaimTier(ctx, alert.ID, budget.StartDate, tier, newTiers)
+	if err != nil || !claimed {
+		// Another evaluation has already sent this tier.
+		return err
+	}
+
+	message := &domain.AlertMessage{
+		Event:           "budget.alert",
+		AlertID:         alert.ID,
+		BudgetID:        budget.ID,
+		BudgetName:      budget.Name,
+		CategoryID:      alert.CategoryID,
+		CategoryName:    categoryName,
+		AlertType:       alert.Type,
+		Tier:            tier,
+		Currency:        budget.Currency,
+		SpentAmount:     spent,
+		TotalAmount:     total,
+		PercentageSpent: spent.Percent(total),
+		Text:            alertText(alert.Type, tier, budget, categoryName),
+		TriggeredAt:     time.Now(),
+	}
 
-			now := time.Now()
-			alert.LastSentAt = &now
-			s.alertRepo.Update(ctx, alert)
+	delivered, err := s.notify(ctx, alert, user, message)
+	if !delivered {
+		return errors.Join(
+			fmt.Errorf("alert %s was not delivered on any channel", alert.ID.Hex()),
+			err,
+			s.alertRepo.ReleaseTier(ctx, alert.ID, budget.StartDate, tier),
+		)
+	}
+	return err
+}
 
-			notification := &domain.AlertNotification{
-				UserID:    alert.UserID,
-				AlertID:   alert.ID,
-				BudgetID:  alert.BudgetID,
-				Message:   fmt.Sprintf("Budget '%s' has reached %.0f%% of limit", budget.Name, percentageSpent),
-				IsSuccess: true,
-			}
-			s.alertRepo.CreateNotification(ctx, notification)
+// alertText describes a fired tier, for example "Category 'Food' in budget
+// 'May' has reached 80% of its limit".
+func alertText(alertType domain.AlertType, tier domain.Money, budget *domain.Budget, categoryName string) string {
+	subject := fmt.Sprintf("Budget '%s'", budget.Name)
+	if categoryName != "" {
+		subject = fmt.Sprintf("Category '%s' in budget '%s'", categoryName, budget.Name)
+	}
+
+	if alertType == domain.AlertTypeAmount {
+		return fmt.Sprintf("%s has reached %s %s of spending", subject, tier, budget.Currency)
+	}
+	return fmt.Sprintf("%s has reached %.0f%% of its limit", subject, tier.Float64())
+}
+
+// notify delivers the message on each of the alert's channels and records
+// every attempt. It reports whether any channel delivered it, and any error
+// recording the attempts. If no channel delivered it the alert is tried again
+// on the next run.
+func (s *alertService) notify(ctx context.Context, alert *domain.Alert, user *domain.User, message *domain.AlertMessage) (bool, error) {
+	delivered := false
+	var errs []error
+	for _, channel := range alert.Channels {
+		notification := &domain.AlertNotification{
+			UserID:   alert.UserID,
+			AlertID:  alert.ID,
+			BudgetID: alert.BudgetID,
+			Channel:  channel.Type,
+			Message:  message.Text,
+		}
+
+		notifier, ok := s.notifiers[channel.Type]
+		if !ok {
+			notification.Error = "unsupported channel"
+		} else if err := notifier.Notify(ctx, user, channel, message); err != nil {
+			notification.Error = err.Error()
+		} else {
+			notification.IsSuccess = true
+			delivered = true
+		}
+
+		if err := s.alertRepo.CreateNotification(ctx, notification); err != nil {
+			errs = append(errs, err)
 		}
 	}
+	return delivered, errors.Join(errs...)
+}
 
-	return nil
+// alertChannels checks the channels of an alert request. Webhook and slack
+// channels need a URL, and a webhook needs a secret unless current already has
+// one for the same URL. Email and in-app can each be chosen once. No channels
+// means email.
+func alertChannels(requested []domain.AlertChannelRequest, current []domain.AlertChannel) ([]domain.AlertChannel, error) {
+	if len(requested) == 0 {
+		return []domain.AlertChannel{{Type: domain.NotificationChannelEmail}}, nil
+	}
+
+	channels := make([]domain.AlertChannel, 0, len(requested))
+	seen := make(map[domain.AlertChannel]bool, len(requested))
+	for _, req := range requested {
+		channel := domain.AlertChannel{Type: req.Type}
+
+		switch req.Type {
+		case domain.NotificationChannelWebhook:
+			if req.URL == "" {
+				return nil, domain.ErrInvalidInput
+			}
+			channel.URL = req.URL
+			channel.Secret = req.Secret
+			if channel.Secret == "" {
+				for _, existing := range current {
+					if existing.Type == req.Type && existing.URL == req.URL {
+						channel.Secret = existing.Secret
+					}
+				}
+			}
+			if channel.Secret == "" {
+				return nil, domain.ErrInvalidInput
+			}
+		case domain.NotificationChannelSlack:
+			if req.URL == "" {
+				return nil, domain.ErrInvalidInput
+			}
+			channel.URL = req.URL
+		}
+
+		key := domain.AlertChannel{Type: channel.Type, URL: channel.URL}
+		if seen[key] {
+			return nil, domain.ErrInvalidInput
+		}
+		seen[key] = true
+
+		channels = append(channels, channel)
+	}
+	return channels, nil
 }
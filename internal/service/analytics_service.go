@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const analyticsCacheTTL = 30 * time.Minute
+
+type AnalyticsService interface {
+	SpendByCategory(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.CategoryTotal, error)
+	SpendOverTime(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.SpendPoint, error)
+	TopDescriptions(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.DescriptionTotal, error)
+	BudgetVsActual(ctx context.Context, userID, budgetID primitive.ObjectID, periods int) ([]domain.PeriodVsActual, error)
+}
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	budgetRepo    repository.BudgetRepository
+	expenseRepo   repository.ExpenseRepository
+	categoryRepo  repository.CategoryRepository
+	cache         cache.CacheService
+}
+
+func NewAnalyticsService(
+	analyticsRepo repository.AnalyticsRepository,
+	budgetRepo repository.BudgetRepository,
+	expenseRepo repository.ExpenseRepository,
+	categoryRepo repository.CategoryRepository,
+	cache cache.CacheService,
+) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+		budgetRepo:    budgetRepo,
+		expenseRepo:   expenseRepo,
+		categoryRepo:  categoryRepo,
+		cache:         cache,
+	}
+}
+
+func (s *analyticsService) SpendByCategory(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.CategoryTotal, error) {
+	if err := s.authorize(ctx, query); err != nil {
+		return nil, err
+	}
+
+	return cachedReport(ctx, s.cache, analyticsKey(query, "by-category"), func() ([]domain.CategoryTotal, error) {
+		totals, err := s.analyticsRepo.SpendByCategory(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		tree, err := s.categoryTree(ctx, totals)
+		if err != nil {
+			return nil, err
+		}
+
+		return rollUpCategories(totals, tree, query.Rollup), nil
+	})
+}
+
+// categoryTree loads the categories of the report rows and all of their
+// ancestors.
+func (s *analyticsService) categoryTree(ctx context.Context, totals []domain.CategoryTotal) (*domain.CategoryTree, error) {
+	loaded := make(map[primitive.ObjectID]bool)
+	var pending []primitive.ObjectID
+	for _, total := range totals {
+		if !loaded[total.CategoryID] {
+			loaded[total.CategoryID] = true
+			pending = append(pending, total.CategoryID)
+		}
+	}
+
+	var categories []*domain.Category
+	for len(pending) > 0 {
+		found, err := s.categoryRepo.FindByIDs(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = nil
+		for _, category := range found {
+			categories = append(categories, category)
+			if category.ParentID != nil && !loaded[*category.ParentID] {
+				loaded[*category.ParentID] = true
+				pending = append(pending, *category.ParentID)
+			}
+		}
+	}
+
+	return domain.NewCategoryTree(categories), nil
+}
+
+// rollUpCategories sets the parent of each row. With rollup, the spend of
+// every category is also added to each of its ancestors, adding rows for
+// ancestors with no spend of their own.
+func rollUpCategories(totals []domain.CategoryTotal, tree *domain.CategoryTree, rollup bool) []domain.CategoryTotal {
+	type rowKey struct {
+		categoryID primitive.ObjectID
+		currency   domain.Currency
+	}
+
+	rows := make([]domain.CategoryTotal, 0, len(totals))
+	index := make(map[rowKey]int, len(totals))
+	for _, total := range totals {
+		if category := tree.Find(total.CategoryID); category != nil {
+			total.ParentID = category.ParentID
+		}
+		index[rowKey{total.CategoryID, total.Currency}] = len(rows)
+		rows = append(rows, total)
+	}
+
+	if !rollup {
+		return rows
+	}
+
+	for _, total := range totals {
+		for _, ancestor := range tree.Ancestors(total.CategoryID) {
+			key := rowKey{ancestor.ID, total.Currency}
+			i, ok := index[key]
+			if !ok {
+				i = len(rows)
+				index[key] = i
+				rows = append(rows, domain.CategoryTotal{
+					CategoryID: ancestor.ID,
+					Category:   ancestor.Name,
+					ParentID:   ancestor.ParentID,
+					Currency:   total.Currency,
+				})
+			}
+			rows[i].Total += total.Total
+			rows[i].Count += total.Count
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Total != rows[j].Total {
+			return rows[i].Total > rows[j].Total
+		}
+		return rows[i].Category < rows[j].Category
+	})
+
+	return rows
+}
+
+func (s *analyticsService) SpendOverTime(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.SpendPoint, error) {
+	if err := s.authorize(ctx, query); err != nil {
+		return nil, err
+	}
+
+	return cachedReport(ctx, s.cache, analyticsKey(query, "over-time"), func() ([]domain.SpendPoint, error) {
+		return s.analyticsRepo.SpendOverTime(ctx, query)
+	})
+}
+
+func (s *analyticsService) TopDescriptions(ctx context.Context, query *domain.AnalyticsQuery) ([]domain.DescriptionTotal, error) {
+	if err := s.authorize(ctx, query); err != nil {
+		return nil, err
+	}
+
+	return cachedReport(ctx, s.cache, analyticsKey(query, "top-descriptions"), func() ([]domain.DescriptionTotal, error) {
+		return s.analyticsRepo.TopDescriptions(ctx, query)
+	})
+}
+
+// BudgetVsActual compares the budget and up to periods-1 of its previous
+// periods with the spend recorded against them, oldest period first.
+func (s *analyticsService) BudgetVsActual(ctx context.Context, userID, budgetID primitive.ObjectID, periods int) ([]domain.PeriodVsActual, error) {
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := budget.Authorize(userID, domain.MemberRoleViewer); err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("analytics:user:%s:budget-vs-actual:%s:%d", userID.Hex(), budgetID.Hex(), periods)
+	return cachedReport(ctx, s.cache, key, func() ([]domain.PeriodVsActual, error) {
+		budgets := []*domain.Budget{budget}
+		for previousID := budget.PreviousBudgetID; previousID != nil && len(budgets) < periods; {
+			previous, err := s.budgetRepo.FindByID(ctx, *previousID)
+			if err != nil {
+				if err == domain.ErrBudgetNotFound {
+					break
+				}
+				return nil, err
+			}
+			budgets = append(budgets, previous)
+			previousID = previous.PreviousBudgetID
+		}
+
+		ids := make([]primitive.ObjectID, len(budgets))
+		for i, b := range budgets {
+			ids[i] = b.ID
+		}
+		spend, err := s.expenseRepo.SumSpendByBudget(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		actual := groupSpendByBudget(spend)
+
+		report := make([]domain.PeriodVsActual, len(budgets))
+		for i, b := range budgets {
+			report[len(budgets)-1-i] = periodVsActual(b, actual[b.ID])
+		}
+		return report, nil
+	})
+}
+
+func periodVsActual(budget *domain.Budget, actual map[primitive.ObjectID]domain.Money) domain.PeriodVsActual {
+	period := domain.PeriodVsActual{
+		BudgetID:   budget.ID,
+		StartDate:  budget.StartDate,
+		EndDate:    budget.EndDate,
+		Currency:   budget.Currency,
+		Categories: make([]domain.CategoryVsActual, len(budget.Categories)),
+	}
+
+	for i, category := range budget.Categories {
+		period.Categories[i] = domain.CategoryVsActual{
+			CategoryID: category.CategoryID,
+			Name:       category.Name,
+			Budgeted:   category.Amount,
+			Actual:     actual[category.CategoryID],
+			Variance:   category.Amount - actual[category.CategoryID],
+		}
+		period.Budgeted += category.Amount
+		period.Actual += actual[category.CategoryID]
+	}
+	period.Variance = period.Budgeted - period.Actual
+
+	return period
+}
+
+// authorize checks that a report scoped to a budget is only run by its
+// members. Reports across all of a user's expenses are scoped to the budgets
+// the user can see.
+func (s *analyticsService) authorize(ctx context.Context, query *domain.AnalyticsQuery) error {
+	if query.BudgetID == nil {
+		budgetIDs, err := accessibleBudgetIDs(ctx, s.budgetRepo, query.UserID)
+		if err != nil {
+			return err
+		}
+		query.BudgetIDs = budgetIDs
+		return nil
+	}
+
+	budget, err := s.budgetRepo.FindByID(ctx, *query.BudgetID)
+	if err != nil {
+		return err
+	}
+
+	return budget.Authorize(query.UserID, domain.MemberRoleViewer)
+}
+
+// cachedReport returns the report cached under key, loading and caching it on
+// a miss.
+func cachedReport[T any](ctx context.Context, c cache.CacheService, key string, load func() (T, error)) (T, error) {
+	var report T
+	if err := c.Get(ctx, key, &report); err == nil {
+		return report, nil
+	}
+
+	report, err := load()
+	if err != nil {
+		return report, err
+	}
+
+	c.Set(ctx, key, report, analyticsCacheTTL)
+
+	return report, nil
+}
+
+// analyticsKey builds the cache key of a report. Keys live under the
+// requesting user so invalidateBudgetCache can drop them per member.
+func analyticsKey(query *domain.AnalyticsQuery, report string) string {
+	params := []string{report}
+	if query.BudgetID != nil {
+		params = append(params, "budget="+query.BudgetID.Hex())
+	}
+	if query.From != nil {
+		params = append(params, "from="+query.From.UTC().Format(time.RFC3339Nano))
+	}
+	if query.To != nil {
+		params = append(params, "to="+query.To.UTC().Format(time.RFC3339Nano))
+	}
+	if query.Interval != "" {
+		params = append(params, "interval="+string(query.Interval), "tz="+query.Timezone)
+	}
+	if query.Rollup {
+		params = append(params, "rollup")
+	}
+	if query.Limit > 0 {
+		params = append(params, fmt.Sprintf("limit=%d", query.Limit))
+	}
+
+	return "analytics:user:" + query.UserID.Hex() + ":" + strings.Join(params, ":")
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/dmehra2102/budget-tracker/internal/domain"
 	"github.com/dmehra2102/budget-tracker/internal/repository"
 	"github.com/dmehra2102/budget-tracker/internal/utils"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AuthService interface {
@@ -21,26 +20,30 @@ type AuthService interface {
 	ForgotPassword(ctx context.Context, req *domain.ForgotPasswordRequest) error
 	ResetPassword(ctx context.Context, req *domain.ResetPasswordRequest) error
 	RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthResponse, error)
+	Logout(ctx context.Context, refreshToken string) error
 }
 
 type authService struct {
-	userRepo     repository.UserRepository
-	emailService EmailService
-	cfg          *config.Config
-	jwtAuth      *utils.JWTAuth
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	emailService     EmailService
+	cfg              *config.Config
+	jwtAuth          *utils.JWTAuth
 }
 
 func NewAuthService(
 	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
 	emailService EmailService,
 	cfg *config.Config,
 	jwtAuth *utils.JWTAuth,
 ) AuthService {
 	return &authService{
-		userRepo:     userRepo,
-		emailService: emailService,
-		cfg:          cfg,
-		jwtAuth:      jwtAuth,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		emailService:     emailService,
+		cfg:              cfg,
+		jwtAuth:          jwtAuth,
 	}
 }
 
@@ -70,23 +73,14 @@ func (s *authService) Register(ctx context.Context, req *domain.RegisterRequest)
 		return nil, err
 	}
 
-	accessToken, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
-	if err != nil {
-		return nil, err
-	}
-
-	refreshToken, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
+	authResponse, err := s.issueTokens(ctx, user, generateSecureToken(16), generateSecureToken(16))
 	if err != nil {
 		return nil, err
 	}
 
 	go s.emailService.SendWelcomeEmail(context.Background(), user.Email, user.FirstName)
 
-	return &domain.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         user,
-	}, nil
+	return authResponse, nil
 }
 
 func (s *authService) Login(ctx context.Context, req *domain.LoginRequest) (*domain.AuthResponse, error) {
@@ -108,21 +102,7 @@ func (s *authService) Login(ctx context.Context, req *domain.LoginRequest) (*dom
 		log.Printf("error while updating user from login function : %v", err)
 	}
 
-	accessToken, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
-	if err != nil {
-		return nil, err
-	}
-
-	refreshToken, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
-	if err != nil {
-		return nil, err
-	}
-
-	return &domain.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         user,
-	}, nil
+	return s.issueTokens(ctx, user, generateSecureToken(16), generateSecureToken(16))
 }
 
 func (s *authService) ForgotPassword(ctx context.Context, req *domain.ForgotPasswordRequest) error {
@@ -166,41 +146,107 @@ func (s *authService) ResetPassword(ctx context.Context, req *domain.ResetPasswo
 		return err
 	}
 
+	// Sign out every existing session after a password change.
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
 	go s.emailService.SendPasswordChangedEmail(ctx, user.Email, user.FirstName)
 
 	return nil
 }
 
 func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthResponse, error) {
-	claims, err := s.jwtAuth.ValidateToken(refreshToken)
+	claims, err := s.jwtAuth.ValidateRefreshToken(refreshToken)
 	if err != nil {
+		if err == domain.ErrTokenExpired {
+			return nil, err
+		}
 		return nil, domain.ErrInvalidToken
 	}
 
-	// Get user ID from claims
-	userID, err := primitive.ObjectIDFromHex(claims.UserID)
+	stored, err := s.refreshTokenRepo.FindByTokenID(ctx, claims.ID)
 	if err != nil {
+		return nil, err
+	}
+
+	if stored.UserID.Hex() != claims.UserID {
 		return nil, domain.ErrInvalidToken
 	}
 
-	user, err := s.userRepo.FindByID(ctx, userID)
+	// A refresh token can only be exchanged once. Presenting an already
+	// rotated token means it has leaked, so the whole family is revoked.
+	if stored.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrTokenReused
+	}
+
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	newTokenID := generateSecureToken(16)
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, stored.TokenID, newTokenID)
 	if err != nil {
 		return nil, err
 	}
+	if !revoked {
+		// Lost a race against another refresh with the same token.
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrTokenReused
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID, newTokenID)
+}
+
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := s.jwtAuth.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		if err == domain.ErrTokenExpired {
+			// Nothing left to revoke.
+			return nil
+		}
+		return domain.ErrInvalidToken
+	}
+
+	stored, err := s.refreshTokenRepo.FindByTokenID(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
 
+// issueTokens creates an access token and a refresh token belonging to the
+// given token family, persisting the refresh token so it can be rotated.
+func (s *authService) issueTokens(ctx context.Context, user *domain.User, familyID, tokenID string) (*domain.AuthResponse, error) {
 	accessToken, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
+	refreshToken, expiresAt, err := s.jwtAuth.GenerateRefreshToken(user.ID.Hex(), user.Email, tokenID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.refreshTokenRepo.Create(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		TokenID:   tokenID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
 	return &domain.AuthResponse{
 		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
+		RefreshToken: refreshToken,
 		User:         user,
 	}, nil
 }
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+type BudgetMemberService interface {
+	InviteMember(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.InviteMemberRequest) (*domain.BudgetInvitation, error)
+	GetBudgetInvitations(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.BudgetInvitation, error)
+	RevokeInvitation(ctx context.Context, userID, budgetID, invitationID primitive.ObjectID) error
+	GetUserInvitations(ctx context.Context, userID primitive.ObjectID) ([]*domain.BudgetInvitation, error)
+	AcceptInvitation(ctx context.Context, userID primitive.ObjectID, token string) (*domain.Budget, error)
+	GetMembers(ctx context.Context, userID, budgetID primitive.ObjectID) ([]domain.BudgetMember, error)
+	UpdateMemberRole(ctx context.Context, userID, budgetID, memberID primitive.ObjectID, req *domain.UpdateMemberRequest) error
+	RemoveMember(ctx context.Context, userID, budgetID, memberID primitive.ObjectID) error
+}
+
+type budgetMemberService struct {
+	uow            repository.UnitOfWork
+	budgetRepo     repository.BudgetRepository
+	invitationRepo repository.BudgetInvitationRepository
+	userRepo       repository.UserRepository
+	emailService   EmailService
+	cache          cache.CacheService
+}
+
+func NewBudgetMemberService(
+	uow repository.UnitOfWork,
+	budgetRepo repository.BudgetRepository,
+	invitationRepo repository.BudgetInvitationRepository,
+	userRepo repository.UserRepository,
+	emailService EmailService,
+	cache cache.CacheService,
+) BudgetMemberService {
+	return &budgetMemberService{
+		uow:            uow,
+		budgetRepo:     budgetRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		emailService:   emailService,
+		cache:          cache,
+	}
+}
+
+// InviteMember emails an invitation to share the budget. The invitee does not
+// need an account yet; the invitation is accepted once they sign in with the
+// invited email address.
+func (s *budgetMemberService) InviteMember(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.InviteMemberRequest) (*domain.BudgetInvitation, error) {
+	budget, err := s.findBudget(ctx, userID, budgetID, domain.MemberRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil && err != domain.ErrUserNotFound {
+		return nil, err
+	}
+	if invitee != nil && budget.RoleOf(invitee.ID) != "" {
+		return nil, domain.ErrAlreadyMember
+	}
+
+	inviter, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &domain.BudgetInvitation{
+		BudgetID:   budgetID,
+		BudgetName: budget.Name,
+		InvitedBy:  userID,
+		Email:      strings.ToLower(req.Email),
+		Role:       req.Role,
+		Token:      generateSecureToken(32),
+		ExpiresAt:  time.Now().Add(invitationTTL),
+	}
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	go s.emailService.SendBudgetInvitationEmail(context.Background(), invitation.Email, inviter.FirstName, budget.Name, invitation.Token)
+
+	return invitation, nil
+}
+
+func (s *budgetMemberService) GetBudgetInvitations(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.BudgetInvitation, error) {
+	if _, err := s.findBudget(ctx, userID, budgetID, domain.MemberRoleOwner); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.FindPendingByBudgetID(ctx, budgetID)
+}
+
+func (s *budgetMemberService) RevokeInvitation(ctx context.Context, userID, budgetID, invitationID primitive.ObjectID) error {
+	if _, err := s.findBudget(ctx, userID, budgetID, domain.MemberRoleOwner); err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+
+	if invitation.BudgetID != budgetID {
+		return domain.ErrInvitationNotFound
+	}
+
+	return s.invitationRepo.Revoke(ctx, invitationID)
+}
+
+func (s *budgetMemberService) GetUserInvitations(ctx context.Context, userID primitive.ObjectID) ([]*domain.BudgetInvitation, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.FindPendingByEmail(ctx, user.Email)
+}
+
+// AcceptInvitation adds the user to the budget with the invited role. Only
+// the user the invitation was addressed to can accept it.
+func (s *budgetMemberService) AcceptInvitation(ctx context.Context, userID primitive.ObjectID, token string) (*domain.Budget, error) {
+	invitation, err := s.invitationRepo.FindPendingByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	var budget *domain.Budget
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.Accept(ctx, invitation.ID, userID); err != nil {
+			return err
+		}
+
+		member := domain.BudgetMember{
+			UserID:   userID,
+			Role:     invitation.Role,
+			JoinedAt: time.Now(),
+		}
+		if err := s.budgetRepo.AddMember(ctx, invitation.BudgetID, member); err != nil {
+			return err
+		}
+
+		var err error
+		budget, err = s.budgetRepo.FindByID(ctx, invitation.BudgetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return budget, nil
+}
+
+// GetMembers lists everyone the budget is shared with, starting with its
+// owner.
+func (s *budgetMemberService) GetMembers(ctx context.Context, userID, budgetID primitive.ObjectID) ([]domain.BudgetMember, error) {
+	budget, err := s.findBudget(ctx, userID, budgetID, domain.MemberRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]domain.BudgetMember, 0, len(budget.Members)+1)
+	members = append(members, domain.BudgetMember{
+		UserID:   budget.UserID,
+		Role:     domain.MemberRoleOwner,
+		JoinedAt: budget.CreatedAt,
+	})
+	members = append(members, budget.Members...)
+
+	return members, nil
+}
+
+func (s *budgetMemberService) UpdateMemberRole(ctx context.Context, userID, budgetID, memberID primitive.ObjectID, req *domain.UpdateMemberRequest) error {
+	budget, err := s.findBudget(ctx, userID, budgetID, domain.MemberRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.SetMemberRole(ctx, budgetID, memberID, req.Role); err != nil {
+		return err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return nil
+}
+
+// RemoveMember takes a member off the budget. The owner can remove anyone and
+// any member can remove themselves.
+func (s *budgetMemberService) RemoveMember(ctx context.Context, userID, budgetID, memberID primitive.ObjectID) error {
+	role := domain.MemberRoleOwner
+	if memberID == userID {
+		role = domain.MemberRoleViewer
+	}
+
+	budget, err := s.findBudget(ctx, userID, budgetID, role)
+	if err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.RemoveMember(ctx, budgetID, memberID); err != nil {
+		return err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return nil
+}
+
+func (s *budgetMemberService) findBudget(ctx context.Context, userID, budgetID primitive.ObjectID, role domain.MemberRole) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := budget.Authorize(userID, role); err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
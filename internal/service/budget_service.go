@@ -14,64 +14,213 @@ type BudgetService interface {
 	CreateBudget(ctx context.Context, userID primitive.ObjectID, req *domain.CreateBudgetRequest) (*domain.Budget, error)
 	GetBudget(ctx context.Context, userID, budgetID primitive.ObjectID) (*domain.Budget, error)
 	GetUserBudgets(ctx context.Context, userID primitive.ObjectID) ([]*domain.Budget, error)
-	UpdateBudget(ctx context.Context, userID primitive.ObjectID, budgetID primitive.ObjectID, req *domain.UpdateBudgetRequest) (*domain.Budget, error)
+	UpdateBudget(ctx context.Context, userID primitive.ObjectID, budgetID primitive.ObjectID, req *domain.UpdateBudgetRequest, version *int64) (*domain.Budget, error)
 	DeleteBudget(ctx context.Context, userID, budgetID primitive.ObjectID) error
+	CreateBudgetFromTemplate(ctx context.Context, userID, templateID primitive.ObjectID, req *domain.NewBudgetRequest) (*domain.Budget, error)
+	CloneBudget(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.NewBudgetRequest) (*domain.Budget, error)
 }
 
 type budgetService struct {
-	budgetRepo repository.BudgetRepository
-	cache      cache.CacheService
+	budgetRepo      repository.BudgetRepository
+	templateRepo    repository.BudgetTemplateRepository
+	categoryService CategoryService
+	cache           cache.CacheService
+	defaultCurrency domain.Currency
 }
 
 func NewBudgetService(
 	budgetRepo repository.BudgetRepository,
+	templateRepo repository.BudgetTemplateRepository,
+	categoryService CategoryService,
 	cache cache.CacheService,
+	defaultCurrency domain.Currency,
 ) BudgetService {
 	return &budgetService{
-		budgetRepo: budgetRepo,
-		cache:      cache,
+		budgetRepo:      budgetRepo,
+		templateRepo:    templateRepo,
+		categoryService: categoryService,
+		cache:           cache,
+		defaultCurrency: defaultCurrency,
 	}
 }
 
 func (s *budgetService) CreateBudget(ctx context.Context, userID primitive.ObjectID, req *domain.CreateBudgetRequest) (*domain.Budget, error) {
-	var endDate time.Time
-	if req.Period == domain.BudgetPeriodWeekly {
-		endDate = req.StartDate.AddDate(0, 0, 7)
-	} else {
-		endDate = req.StartDate.AddDate(0, 1, 0)
+	budget := &domain.Budget{
+		UserID:     userID,
+		Name:       req.Name,
+		Period:     req.Period,
+		Mode:       req.Mode,
+		Currency:   req.Currency,
+		CarryOver:  req.CarryOver,
+		StartDate:  req.StartDate,
+		Categories: req.Categories,
+	}
+
+	if err := s.create(ctx, budget, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+func (s *budgetService) CreateBudgetFromTemplate(ctx context.Context, userID, templateID primitive.ObjectID, req *domain.NewBudgetRequest) (*domain.Budget, error) {
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, err
 	}
 
-	totalAmount := 0.0
-	for _, category := range req.Categories {
-		totalAmount += category.Amount
+	if template.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	name := req.Name
+	if name == "" {
+		name = template.Name
 	}
 
 	budget := &domain.Budget{
-		UserID:      userID,
-		Name:        req.Name,
-		Period:      req.Period,
-		StartDate:   req.StartDate,
-		EndDate:     endDate,
-		Categories:  req.Categories,
-		TotalAmount: totalAmount,
+		UserID:     userID,
+		Name:       name,
+		Period:     template.Period,
+		Currency:   template.Currency,
+		CarryOver:  template.CarryOver,
+		StartDate:  req.StartDate,
+		Categories: template.BudgetCategories(),
+		TemplateID: &template.ID,
 	}
 
-	if err := s.budgetRepo.Create(ctx, budget); err != nil {
+	if err := s.create(ctx, budget, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	return budget, nil
+}
+
+// CloneBudget starts a new budget with the layout of an existing one. Amounts
+// carried into the source are left out, so the clone uses the planned
+// amounts. A custom-period clone without an end date keeps the source length.
+func (s *budgetService) CloneBudget(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.NewBudgetRequest) (*domain.Budget, error) {
+	source, err := s.GetBudget(ctx, userID, budgetID)
+	if err != nil {
 		return nil, err
 	}
 
-	s.cache.Delete(ctx, "budgets:user:"+userID.Hex())
+	name := req.Name
+	if name == "" {
+		name = source.Name
+	}
+
+	categories := make([]domain.BudgetCategory, len(source.Categories))
+	for i, category := range source.Categories {
+		categories[i] = domain.BudgetCategory{
+			CategoryID: category.CategoryID,
+			Name:       category.Name,
+			Amount:     category.Amount - category.CarriedOver,
+		}
+	}
+
+	endDate := req.EndDate
+	if endDate == nil && source.Period == domain.BudgetPeriodCustom {
+		end := req.StartDate.Add(source.EndDate.Sub(source.StartDate))
+		endDate = &end
+	}
+
+	budget := &domain.Budget{
+		UserID:     userID,
+		Name:       name,
+		Period:     source.Period,
+		Mode:       source.Mode,
+		Currency:   source.Currency,
+		CarryOver:  source.CarryOver,
+		StartDate:  req.StartDate,
+		Categories: categories,
+	}
+
+	if err := s.create(ctx, budget, endDate); err != nil {
+		return nil, err
+	}
 
 	return budget, nil
 }
 
+// create fills in the end date, mode, currency and totals of a new budget,
+// resolves its categories against the owner's catalog, clears any spent or
+// carried amounts on them, and stores it. Envelope budgets start with empty
+// envelopes; money is assigned to them from recorded income.
+func (s *budgetService) create(ctx context.Context, budget *domain.Budget, customEnd *time.Time) error {
+	endDate, err := budget.Period.EndDate(budget.StartDate, customEnd)
+	if err != nil {
+		return err
+	}
+	budget.EndDate = endDate
+	budget.PeriodAnchor = budget.StartDate
+	budget.PeriodIndex = 0
+
+	resolver := newCategoryResolver(s.categoryService, budget.UserID, nil)
+	if err := resolveBudgetCategories(ctx, resolver, budget.Categories); err != nil {
+		return err
+	}
+
+	if budget.Mode == "" {
+		budget.Mode = domain.BudgetModeLimit
+	}
+	if budget.Currency == "" {
+		budget.Currency = s.defaultCurrency
+	}
+
+	budget.TotalAmount = 0
+	budget.IncomeAmount = 0
+	for i := range budget.Categories {
+		if budget.IsEnvelope() {
+			budget.Categories[i].Amount = 0
+		}
+		budget.Categories[i].SpentAmount = 0
+		budget.Categories[i].CarriedOver = 0
+		budget.Categories[i].DroppedOverspend = 0
+		budget.TotalAmount += budget.Categories[i].Amount
+	}
+
+	if err := s.budgetRepo.Create(ctx, budget); err != nil {
+		return err
+	}
+
+	s.cache.Delete(ctx, "budgets:user:"+budget.UserID.Hex())
+
+	return nil
+}
+
+// invalidateBudgetCache drops the cached budget, and the cached budget list
+// and analytics reports of everyone it is shared with.
+func invalidateBudgetCache(ctx context.Context, c cache.CacheService, budget *domain.Budget) {
+	c.Delete(ctx, "budget:"+budget.ID.Hex())
+	for _, userID := range budget.MemberIDs() {
+		c.Delete(ctx, "budgets:user:"+userID.Hex())
+		c.DeletePattern(ctx, "analytics:user:"+userID.Hex()+":*")
+	}
+}
+
+// accessibleBudgetIDs returns the IDs of every budget the user owns or is a
+// member of, which scope the queries across all of a user's expenses.
+func accessibleBudgetIDs(ctx context.Context, budgetRepo repository.BudgetRepository, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	budgets, err := budgetRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(budgets))
+	for _, budget := range budgets {
+		ids = append(ids, budget.ID)
+	}
+	return ids, nil
+}
+
 func (s *budgetService) GetBudget(ctx context.Context, userID, budgetID primitive.ObjectID) (*domain.Budget, error) {
 	cacheKey := "budget:" + budgetID.Hex()
 	var budget domain.Budget
 
-	if err := s.cache.Get(ctx, cacheKey, &budget); err != nil {
-		if budget.UserID != userID {
-			return nil, domain.ErrUnauthorized
+	if err := s.cache.Get(ctx, cacheKey, &budget); err == nil {
+		if err := budget.Authorize(userID, domain.MemberRoleViewer); err != nil {
+			return nil, err
 		}
 		return &budget, nil
 	}
@@ -81,8 +230,8 @@ func (s *budgetService) GetBudget(ctx context.Context, userID, budgetID primitiv
 		return nil, err
 	}
 
-	if budgetPtr.UserID != userID {
-		return nil, domain.ErrUnauthorized
+	if err := budgetPtr.Authorize(userID, domain.MemberRoleViewer); err != nil {
+		return nil, err
 	}
 
 	s.cache.Set(ctx, cacheKey, budgetPtr, 1*time.Hour)
@@ -108,19 +257,41 @@ func (s *budgetService) GetUserBudgets(ctx context.Context, userID primitive.Obj
 	return budgets, nil
 }
 
-func (s *budgetService) UpdateBudget(ctx context.Context, userID primitive.ObjectID, budgetID primitive.ObjectID, req *domain.UpdateBudgetRequest) (*domain.Budget, error) {
-	budget, err := s.GetBudget(ctx, userID, budgetID)
+// UpdateBudget applies req to the stored budget. When version is set the
+// budget must still be at that version. The update fails with
+// domain.ErrVersionConflict if the budget changes before it is written.
+func (s *budgetService) UpdateBudget(ctx context.Context, userID primitive.ObjectID, budgetID primitive.ObjectID, req *domain.UpdateBudgetRequest, version *int64) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := budget.Authorize(userID, domain.MemberRoleEditor); err != nil {
+		return nil, err
+	}
+
+	if version != nil && *version != budget.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
 	if req.Name != "" {
 		budget.Name = req.Name
 	}
+	if req.CarryOver != nil {
+		budget.CarryOver = *req.CarryOver
+	}
 	if len(req.Categories) > 0 {
-		budget.Categories = req.Categories
-		totalAmount := 0.0
-		for _, category := range req.Categories {
+		resolver := newCategoryResolver(s.categoryService, budget.UserID, budget.Categories)
+		if err := resolveBudgetCategories(ctx, resolver, req.Categories); err != nil {
+			return nil, err
+		}
+		categories, err := mergeCategorySpend(budget, req.Categories)
+		if err != nil {
+			return nil, err
+		}
+		budget.Categories = categories
+		totalAmount := domain.Money(0)
+		for _, category := range categories {
 			totalAmount += category.Amount
 		}
 		budget.TotalAmount = totalAmount
@@ -130,8 +301,7 @@ func (s *budgetService) UpdateBudget(ctx context.Context, userID primitive.Objec
 		return nil, err
 	}
 
-	s.cache.Delete(ctx, "budget:"+budgetID.Hex())
-	s.cache.Delete(ctx, "budget:user:"+userID.Hex())
+	invalidateBudgetCache(ctx, s.cache, budget)
 
 	return budget, nil
 }
@@ -142,16 +312,54 @@ func (s *budgetService) DeleteBudget(ctx context.Context, userID, budgetID primi
 		return nil
 	}
 
-	if budget.UserID != userID {
-		return domain.ErrUnauthorized
+	if err := budget.Authorize(userID, domain.MemberRoleOwner); err != nil {
+		return err
 	}
 
 	if err := s.budgetRepo.Delete(ctx, budgetID); err != nil {
 		return err
 	}
 
-	s.cache.Delete(ctx, "budget:"+budgetID.Hex())
-	s.cache.Delete(ctx, "budget:user:"+userID.Hex())
+	invalidateBudgetCache(ctx, s.cache, budget)
 
 	return nil
 }
+
+// mergeCategorySpend carries the spent, carried-over and dropped amounts of
+// each existing category over to the updated category list. Spent amounts are
+// maintained by expenses, so a category that still has spending cannot be
+// dropped. In envelope mode assigned amounts only change through transfers,
+// so they are kept as well and an envelope holding money cannot be dropped.
+func mergeCategorySpend(budget *domain.Budget, categories []domain.BudgetCategory) ([]domain.BudgetCategory, error) {
+	merged := make([]domain.BudgetCategory, len(categories))
+	kept := make(map[primitive.ObjectID]bool, len(categories))
+	for i, category := range categories {
+		category.SpentAmount = 0
+		category.CarriedOver = 0
+		category.DroppedOverspend = 0
+		if budget.IsEnvelope() {
+			category.Amount = 0
+		}
+		if existing := budget.FindCategory(category.CategoryID); existing != nil {
+			category.SpentAmount = existing.SpentAmount
+			category.CarriedOver = existing.CarriedOver
+			category.DroppedOverspend = existing.DroppedOverspend
+			if budget.IsEnvelope() {
+				category.Amount = existing.Amount
+			}
+		}
+		merged[i] = category
+		kept[category.CategoryID] = true
+	}
+
+	for _, existing := range budget.Categories {
+		if kept[existing.CategoryID] {
+			continue
+		}
+		if existing.SpentAmount != 0 || (budget.IsEnvelope() && existing.Amount != 0) {
+			return nil, domain.ErrCategoryInUse
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type BudgetTemplateService interface {
+	CreateTemplate(ctx context.Context, userID primitive.ObjectID, req *domain.CreateBudgetTemplateRequest) (*domain.BudgetTemplate, error)
+	GetTemplate(ctx context.Context, userID, templateID primitive.ObjectID) (*domain.BudgetTemplate, error)
+	GetUserTemplates(ctx context.Context, userID primitive.ObjectID) ([]*domain.BudgetTemplate, error)
+	UpdateTemplate(ctx context.Context, userID, templateID primitive.ObjectID, req *domain.CreateBudgetTemplateRequest) (*domain.BudgetTemplate, error)
+	DeleteTemplate(ctx context.Context, userID, templateID primitive.ObjectID) error
+}
+
+type budgetTemplateService struct {
+	templateRepo    repository.BudgetTemplateRepository
+	categoryService CategoryService
+}
+
+func NewBudgetTemplateService(templateRepo repository.BudgetTemplateRepository, categoryService CategoryService) BudgetTemplateService {
+	return &budgetTemplateService{
+		templateRepo:    templateRepo,
+		categoryService: categoryService,
+	}
+}
+
+func (s *budgetTemplateService) CreateTemplate(ctx context.Context, userID primitive.ObjectID, req *domain.CreateBudgetTemplateRequest) (*domain.BudgetTemplate, error) {
+	template := &domain.BudgetTemplate{
+		UserID:     userID,
+		Name:       req.Name,
+		Period:     req.Period,
+		Currency:   req.Currency,
+		CarryOver:  req.CarryOver,
+		Categories: req.Categories,
+	}
+
+	resolver := newCategoryResolver(s.categoryService, userID, nil)
+	if err := resolveTemplateCategories(ctx, resolver, template.Categories); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *budgetTemplateService) GetTemplate(ctx context.Context, userID, templateID primitive.ObjectID) (*domain.BudgetTemplate, error) {
+	template, err := s.templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return template, nil
+}
+
+func (s *budgetTemplateService) GetUserTemplates(ctx context.Context, userID primitive.ObjectID) ([]*domain.BudgetTemplate, error) {
+	return s.templateRepo.FindByUserID(ctx, userID)
+}
+
+func (s *budgetTemplateService) UpdateTemplate(ctx context.Context, userID, templateID primitive.ObjectID, req *domain.CreateBudgetTemplateRequest) (*domain.BudgetTemplate, error) {
+	template, err := s.GetTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newCategoryResolver(s.categoryService, userID, template.BudgetCategories())
+	if err := resolveTemplateCategories(ctx, resolver, req.Categories); err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Period = req.Period
+	template.Currency = req.Currency
+	template.CarryOver = req.CarryOver
+	template.Categories = req.Categories
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *budgetTemplateService) DeleteTemplate(ctx context.Context, userID, templateID primitive.ObjectID) error {
+	if _, err := s.GetTemplate(ctx, userID, templateID); err != nil {
+		return err
+	}
+
+	return s.templateRepo.Delete(ctx, templateID)
+}
+
+func resolveTemplateCategories(ctx context.Context, r *categoryResolver, lines []domain.TemplateCategory) error {
+	for i := range lines {
+		id, name, err := r.resolve(ctx, lines[i].CategoryID, lines[i].Name)
+		if err != nil {
+			return err
+		}
+		lines[i].CategoryID = id
+		lines[i].Name = name
+	}
+	return nil
+}
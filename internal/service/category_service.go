@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, userID primitive.ObjectID, req *domain.CreateCategoryRequest) (*domain.Category, error)
+	GetCategory(ctx context.Context, userID, categoryID primitive.ObjectID) (*domain.Category, error)
+	GetUserCategories(ctx context.Context, userID primitive.ObjectID, includeArchived bool) ([]*domain.Category, error)
+	UpdateCategory(ctx context.Context, userID, categoryID primitive.ObjectID, req *domain.UpdateCategoryRequest) (*domain.Category, error)
+	ResolveCategory(ctx context.Context, userID, categoryID primitive.ObjectID, name string) (*domain.Category, error)
+}
+
+type categoryService struct {
+	uow           repository.UnitOfWork
+	categoryRepo  repository.CategoryRepository
+	budgetRepo    repository.BudgetRepository
+	templateRepo  repository.BudgetTemplateRepository
+	expenseRepo   repository.ExpenseRepository
+	recurringRepo repository.RecurringExpenseRepository
+	goalRepo      repository.GoalRepository
+	cache         cache.CacheService
+}
+
+func NewCategoryService(
+	uow repository.UnitOfWork,
+	categoryRepo repository.CategoryRepository,
+	budgetRepo repository.BudgetRepository,
+	templateRepo repository.BudgetTemplateRepository,
+	expenseRepo repository.ExpenseRepository,
+	recurringRepo repository.RecurringExpenseRepository,
+	goalRepo repository.GoalRepository,
+	cache cache.CacheService,
+) CategoryService {
+	return &categoryService{
+		uow:           uow,
+		categoryRepo:  categoryRepo,
+		budgetRepo:    budgetRepo,
+		templateRepo:  templateRepo,
+		expenseRepo:   expenseRepo,
+		recurringRepo: recurringRepo,
+		goalRepo:      goalRepo,
+		cache:         cache,
+	}
+}
+
+func (s *categoryService) CreateCategory(ctx context.Context, userID primitive.ObjectID, req *domain.CreateCategoryRequest) (*domain.Category, error) {
+	category := &domain.Category{
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+		Icon:   req.Icon,
+	}
+
+	if req.ParentID != "" {
+		parent, err := s.findParent(ctx, userID, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = &parent.ID
+	}
+
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *categoryService) GetCategory(ctx context.Context, userID, categoryID primitive.ObjectID) (*domain.Category, error) {
+	category, err := s.categoryRepo.FindByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if category.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return category, nil
+}
+
+func (s *categoryService) GetUserCategories(ctx context.Context, userID primitive.ObjectID, includeArchived bool) ([]*domain.Category, error) {
+	categories, err := s.categoryRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if includeArchived {
+		return categories, nil
+	}
+
+	active := []*domain.Category{}
+	for _, category := range categories {
+		if !category.IsArchived {
+			active = append(active, category)
+		}
+	}
+	return active, nil
+}
+
+// UpdateCategory edits a category. A rename is copied onto the budgets,
+// templates, expenses and recurring expenses that use the category in the
+// same transaction. A category cannot be moved under one of its own
+// subcategories, nor restored while its parent is archived.
+func (s *categoryService) UpdateCategory(ctx context.Context, userID, categoryID primitive.ObjectID, req *domain.UpdateCategoryRequest) (*domain.Category, error) {
+	category, err := s.GetCategory(ctx, userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tree := domain.NewCategoryTree(categories)
+
+	renamed := req.Name != "" && req.Name != category.Name
+	if renamed {
+		category.Name = req.Name
+	}
+	if req.Color != nil {
+		category.Color = *req.Color
+	}
+	if req.Icon != nil {
+		category.Icon = *req.Icon
+	}
+	oldParent := category.ParentID
+	if req.ParentID != nil {
+		category.ParentID = nil
+		if *req.ParentID != "" {
+			parent, err := s.findParent(ctx, userID, *req.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent.ID == category.ID || tree.IsDescendant(parent.ID, category.ID) {
+				return nil, domain.ErrInvalidParent
+			}
+			category.ParentID = &parent.ID
+		}
+	}
+
+	var archive []primitive.ObjectID
+	if req.IsArchived != nil && *req.IsArchived != category.IsArchived {
+		if !*req.IsArchived && category.ParentID != nil {
+			if parent := tree.Find(*category.ParentID); parent != nil && parent.IsArchived {
+				return nil, domain.ErrCategoryArchived
+			}
+		}
+		archive = append(archive, category.ID)
+		for _, descendant := range tree.Descendants(category.ID) {
+			archive = append(archive, descendant.ID)
+		}
+		category.IsArchived = *req.IsArchived
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.Update(ctx, category); err != nil {
+			return err
+		}
+
+		if len(archive) > 0 {
+			if err := s.categoryRepo.SetArchived(ctx, archive, category.IsArchived); err != nil {
+				return err
+			}
+		}
+
+		if !renamed {
+			return nil
+		}
+		if err := s.budgetRepo.RenameCategory(ctx, category.ID, category.Name); err != nil {
+			return err
+		}
+		if err := s.templateRepo.RenameCategory(ctx, category.ID, category.Name); err != nil {
+			return err
+		}
+		if err := s.expenseRepo.RenameCategory(ctx, category.ID, category.Name); err != nil {
+			return err
+		}
+		if err := s.recurringRepo.RenameCategory(ctx, category.ID, category.Name); err != nil {
+			return err
+		}
+		return s.goalRepo.RenameCategory(ctx, category.ID, category.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A move changes the rollups of the category and everything under it.
+	moved := !sameObjectID(oldParent, category.ParentID)
+	if moved {
+		ids := []primitive.ObjectID{category.ID}
+		for _, descendant := range tree.Descendants(category.ID) {
+			ids = append(ids, descendant.ID)
+		}
+		s.cache.DeletePattern(ctx, "analytics:user:"+userID.Hex()+":*")
+		s.invalidateBudgets(ctx, userID, ids...)
+	} else if renamed {
+		s.invalidateBudgets(ctx, userID, category.ID)
+	}
+
+	return category, nil
+}
+
+// ResolveCategory returns the catalog category a budget, template, recurring
+// expense or goal refers to. It is looked up by ID when one is given and by
+// name otherwise, in which case a missing category is added to the catalog at
+// the top level. A category from another user's catalog, as when cloning a
+// budget shared with the user, is matched by its name.
+func (s *categoryService) ResolveCategory(ctx context.Context, userID, categoryID primitive.ObjectID, name string) (*domain.Category, error) {
+	if !categoryID.IsZero() {
+		category, err := s.categoryRepo.FindByID(ctx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category.UserID != userID {
+			return s.ResolveCategory(ctx, userID, primitive.NilObjectID, category.Name)
+		}
+		if category.IsArchived {
+			return nil, domain.ErrCategoryArchived
+		}
+		return category, nil
+	}
+
+	key := domain.CategoryKey(name)
+	if key == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	category, err := s.categoryRepo.FindByKey(ctx, userID, key)
+	if err == domain.ErrCategoryNotFound {
+		category = &domain.Category{
+			UserID: userID,
+			Name:   name,
+		}
+		err = s.categoryRepo.Create(ctx, category)
+		if err == domain.ErrCategoryExists {
+			// Created concurrently by another request.
+			category, err = s.categoryRepo.FindByKey(ctx, userID, key)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if category.IsArchived {
+		return nil, domain.ErrCategoryArchived
+	}
+
+	return category, nil
+}
+
+func (s *categoryService) findParent(ctx context.Context, userID primitive.ObjectID, parentID string) (*domain.Category, error) {
+	id, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+
+	parent, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if parent.UserID != userID {
+		return nil, domain.ErrCategoryNotFound
+	}
+	if parent.IsArchived {
+		return nil, domain.ErrCategoryArchived
+	}
+
+	return parent, nil
+}
+
+// invalidateBudgets drops the cached copies of the user's budgets that track
+// any of the categories.
+func (s *categoryService) invalidateBudgets(ctx context.Context, userID primitive.ObjectID, categoryIDs ...primitive.ObjectID) {
+	budgets, err := s.budgetRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		s.cache.Delete(ctx, "budgets:user:"+userID.Hex())
+		return
+	}
+
+	for _, budget := range budgets {
+		for _, categoryID := range categoryIDs {
+			if budget.FindCategory(categoryID) != nil {
+				invalidateBudgetCache(ctx, s.cache, budget)
+				break
+			}
+		}
+	}
+}
+
+// sameObjectID reports whether two optional IDs are both unset or equal.
+func sameObjectID(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// categoryResolver points the lines of a budget or template at the user's
+// catalog. A category may appear only once. Lines already on the budget or
+// template are kept as they are, so archiving a category does not stop the
+// lines that use it from being edited.
+type categoryResolver struct {
+	categories CategoryService
+	userID     primitive.ObjectID
+	current    map[primitive.ObjectID]string
+	seen       map[primitive.ObjectID]bool
+}
+
+func newCategoryResolver(categories CategoryService, userID primitive.ObjectID, current []domain.BudgetCategory) *categoryResolver {
+	r := &categoryResolver{
+		categories: categories,
+		userID:     userID,
+		current:    make(map[primitive.ObjectID]string, len(current)),
+		seen:       make(map[primitive.ObjectID]bool),
+	}
+	for _, line := range current {
+		r.current[line.CategoryID] = line.Name
+	}
+	return r
+}
+
+func (r *categoryResolver) resolve(ctx context.Context, categoryID primitive.ObjectID, name string) (primitive.ObjectID, string, error) {
+	if currentName, ok := r.current[categoryID]; ok && !categoryID.IsZero() {
+		name = currentName
+	} else {
+		category, err := r.categories.ResolveCategory(ctx, r.userID, categoryID, name)
+		if err != nil {
+			return primitive.NilObjectID, "", err
+		}
+		categoryID, name = category.ID, category.Name
+	}
+
+	if r.seen[categoryID] {
+		return primitive.NilObjectID, "", domain.ErrInvalidInput
+	}
+	r.seen[categoryID] = true
+
+	return categoryID, name, nil
+}
+
+// resolveBudgetCategories resolves every line of a budget in place.
+func resolveBudgetCategories(ctx context.Context, r *categoryResolver, lines []domain.BudgetCategory) error {
+	for i := range lines {
+		id, name, err := r.resolve(ctx, lines[i].CategoryID, lines[i].Name)
+		if err != nil {
+			return err
+		}
+		lines[i].CategoryID = id
+		lines[i].Name = name
+	}
+	return nil
+}
+
+// categorySubtree returns the category and all of its subcategories.
+func categorySubtree(ctx context.Context, categoryRepo repository.CategoryRepository, categoryID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	category, err := categoryRepo.FindByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := categoryRepo.FindByUserID(ctx, category.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []primitive.ObjectID{category.ID}
+	for _, descendant := range domain.NewCategoryTree(categories).Descendants(category.ID) {
+		ids = append(ids, descendant.ID)
+	}
+	return ids, nil
+}
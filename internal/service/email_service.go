@@ -6,6 +6,7 @@ import (
 	"net/smtp"
 
 	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
 )
 
 type EmailService interface {
@@ -13,6 +14,8 @@ type EmailService interface {
 	SendPasswordResetEmail(ctx context.Context, email, firstName, token string) error
 	SendPasswordChangedEmail(ctx context.Context, email, firstName string) error
 	SendBudgetAlertEmail(ctx context.Context, email, firstName, budgetName string, percentage float64) error
+	SendBudgetInvitationEmail(ctx context.Context, email, inviterName, budgetName, token string) error
+	SendGoalBehindScheduleEmail(ctx context.Context, email, firstName, goalName, currency string, saved, expected, requiredMonthly domain.Money) error
 }
 
 type emailService struct {
@@ -90,6 +93,41 @@ func (s *emailService) SendBudgetAlertEmail(ctx context.Context, email, firstNam
 	return s.sendEmail(email, subject, body)
 }
 
+func (s *emailService) SendBudgetInvitationEmail(ctx context.Context, email, inviterName, budgetName, token string) error {
+	subject := fmt.Sprintf("%s shared a budget with you", inviterName)
+	acceptURL := fmt.Sprintf("https://yourapp.com/invitations/accept?token=%s", token)
+
+	body := fmt.Sprintf(`
+        <html>
+            <body>
+                <h2>Budget Invitation</h2>
+                <p>%s has invited you to share the budget "%s".</p>
+                <p>Click the link below to accept the invitation:</p>
+                <a href="%s">Accept Invitation</a>
+                <p>This link expires in 7 days.</p>
+            </body>
+        </html>
+    `, inviterName, budgetName, acceptURL)
+
+	return s.sendEmail(email, subject, body)
+}
+
+func (s *emailService) SendGoalBehindScheduleEmail(ctx context.Context, email, firstName, goalName, currency string, saved, expected, requiredMonthly domain.Money) error {
+	subject := fmt.Sprintf("Savings Goal Behind Schedule: %s", goalName)
+	body := fmt.Sprintf(`
+        <html>
+            <body>
+                <h2>Savings Goal Reminder</h2>
+                <p>Hi %s,</p>
+                <p>Your goal "%s" is behind schedule. You have saved %s %s, but should have %s %s by now.</p>
+                <p>Saving %s %s a month will get you back on track.</p>
+            </body>
+        </html>
+    `, firstName, goalName, saved, currency, expected, currency, requiredMonthly, currency)
+
+	return s.sendEmail(email, subject, body)
+}
+
 func (s *emailService) sendEmail(to, subject, htmlBody string) error {
 	auth := smtp.PlainAuth("", s.cfg.Email.SMTPUsername, s.cfg.Email.SMTPPassword, s.cfg.Email.SMTPHost)
 
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EnvelopeService interface {
+	RecordIncome(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.RecordIncomeRequest) (*domain.EnvelopeTransfer, error)
+	Transfer(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.EnvelopeTransferRequest) (*domain.EnvelopeTransfer, error)
+	GetEnvelopes(ctx context.Context, userID, budgetID primitive.ObjectID) (*domain.EnvelopeSummary, error)
+	GetTransfers(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.EnvelopeTransfer, error)
+}
+
+type envelopeService struct {
+	uow          repository.UnitOfWork
+	budgetRepo   repository.BudgetRepository
+	transferRepo repository.EnvelopeTransferRepository
+	cache        cache.CacheService
+}
+
+func NewEnvelopeService(
+	uow repository.UnitOfWork,
+	budgetRepo repository.BudgetRepository,
+	transferRepo repository.EnvelopeTransferRepository,
+	cache cache.CacheService,
+) EnvelopeService {
+	return &envelopeService{
+		uow:          uow,
+		budgetRepo:   budgetRepo,
+		transferRepo: transferRepo,
+		cache:        cache,
+	}
+}
+
+func (s *envelopeService) RecordIncome(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.RecordIncomeRequest) (*domain.EnvelopeTransfer, error) {
+	transfer := &domain.EnvelopeTransfer{
+		BudgetID: budgetID,
+		UserID:   userID,
+		Type:     domain.TransferTypeIncome,
+		Amount:   req.Amount,
+		Note:     req.Note,
+	}
+
+	var budget *domain.Budget
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		budget, err = s.findEnvelopeBudget(ctx, userID, budgetID, domain.MemberRoleEditor)
+		if err != nil {
+			return err
+		}
+		if err := s.budgetRepo.AddIncome(ctx, budgetID, req.Amount); err != nil {
+			return err
+		}
+		return s.transferRepo.Create(ctx, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return transfer, nil
+}
+
+// Transfer moves money between envelopes or between an envelope and the
+// to-be-assigned pool. Only money that is actually available can be moved,
+// so an overspent envelope cannot be drawn further below zero. The budget is
+// read and written in one transaction, so concurrent transfers on the same
+// budget conflict and are retried rather than both passing the check.
+func (s *envelopeService) Transfer(ctx context.Context, userID, budgetID primitive.ObjectID, req *domain.EnvelopeTransferRequest) (*domain.EnvelopeTransfer, error) {
+	var fromID, toID primitive.ObjectID
+	var err error
+	if req.From != "" {
+		if fromID, err = primitive.ObjectIDFromHex(req.From); err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+	}
+	if req.To != "" {
+		if toID, err = primitive.ObjectIDFromHex(req.To); err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+	}
+
+	transfer := &domain.EnvelopeTransfer{
+		BudgetID: budgetID,
+		UserID:   userID,
+		Type:     domain.TransferTypeMove,
+		Amount:   req.Amount,
+		Note:     req.Note,
+	}
+	switch {
+	case req.From == "":
+		transfer.Type = domain.TransferTypeAssign
+	case req.To == "":
+		transfer.Type = domain.TransferTypeUnassign
+	}
+
+	var budget *domain.Budget
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		budget, err = s.findEnvelopeBudget(ctx, userID, budgetID, domain.MemberRoleEditor)
+		if err != nil {
+			return err
+		}
+
+		if req.From == "" {
+			if budget.ToBeAssigned() < req.Amount {
+				return domain.ErrInsufficientFunds
+			}
+		} else {
+			from := budget.FindCategory(fromID)
+			if from == nil {
+				return domain.ErrCategoryNotFound
+			}
+			if from.Available() < req.Amount {
+				return domain.ErrInsufficientFunds
+			}
+			if err := s.budgetRepo.AdjustEnvelope(ctx, budgetID, fromID, -req.Amount); err != nil {
+				return err
+			}
+			transfer.FromID = &from.CategoryID
+			transfer.From = from.Name
+		}
+
+		if req.To != "" {
+			to := budget.FindCategory(toID)
+			if to == nil {
+				return domain.ErrCategoryNotFound
+			}
+			if err := s.budgetRepo.AdjustEnvelope(ctx, budgetID, toID, req.Amount); err != nil {
+				return err
+			}
+			transfer.ToID = &to.CategoryID
+			transfer.To = to.Name
+		}
+
+		return s.transferRepo.Create(ctx, transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return transfer, nil
+}
+
+func (s *envelopeService) GetEnvelopes(ctx context.Context, userID, budgetID primitive.ObjectID) (*domain.EnvelopeSummary, error) {
+	budget, err := s.findEnvelopeBudget(ctx, userID, budgetID, domain.MemberRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return budget.EnvelopeSummary(), nil
+}
+
+func (s *envelopeService) GetTransfers(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.EnvelopeTransfer, error) {
+	if _, err := s.findEnvelopeBudget(ctx, userID, budgetID, domain.MemberRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.transferRepo.FindByBudgetID(ctx, budgetID)
+}
+
+func (s *envelopeService) findEnvelopeBudget(ctx context.Context, userID, budgetID primitive.ObjectID, role domain.MemberRole) (*domain.Budget, error) {
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := budget.Authorize(userID, role); err != nil {
+		return nil, err
+	}
+
+	if !budget.IsEnvelope() {
+		return nil, domain.ErrNotEnvelopeBudget
+	}
+
+	return budget, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+)
+
+// maxRateAge bounds how far back a rate may be used for a date. Reference
+// rates are not published on weekends and holidays, but a rate older than
+// this means the table has not been loaded for that period.
+const maxRateAge = 7 * 24 * time.Hour
+
+// ExchangeRateProvider returns the rate that converts one unit of from into
+// to on the given date.
+type ExchangeRateProvider interface {
+	Rate(ctx context.Context, from, to domain.Currency, date time.Time) (*big.Rat, error)
+}
+
+type storedRateProvider struct {
+	rateRepo repository.ExchangeRateRepository
+	base     domain.Currency
+}
+
+// NewStoredRateProvider serves rates from the exchange_rates collection. Rates
+// are stored against a single base currency and other pairs are crossed
+// through it.
+func NewStoredRateProvider(rateRepo repository.ExchangeRateRepository, base domain.Currency) ExchangeRateProvider {
+	return &storedRateProvider{
+		rateRepo: rateRepo,
+		base:     base,
+	}
+}
+
+func (p *storedRateProvider) Rate(ctx context.Context, from, to domain.Currency, date time.Time) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	fromRate, err := p.baseRate(ctx, from, date)
+	if err != nil {
+		return nil, err
+	}
+
+	toRate, err := p.baseRate(ctx, to, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Rat).Quo(toRate, fromRate), nil
+}
+
+func (p *storedRateProvider) baseRate(ctx context.Context, currency domain.Currency, date time.Time) (*big.Rat, error) {
+	if currency == p.base {
+		return big.NewRat(1, 1), nil
+	}
+
+	rate, err := p.rateRepo.FindLatest(ctx, p.base, currency, date)
+	if err != nil {
+		return nil, err
+	}
+	if date.Sub(rate.Date) > maxRateAge {
+		return nil, domain.ErrRateNotFound
+	}
+
+	r, err := domain.RatFromDecimal(rate.Rate)
+	if err != nil {
+		return nil, err
+	}
+	if r.Sign() <= 0 {
+		return nil, domain.ErrRateNotFound
+	}
+	return r, nil
+}
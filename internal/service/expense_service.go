@@ -12,26 +12,40 @@ import (
 type ExpenseService interface {
 	CreateExpense(ctx context.Context, userID primitive.ObjectID, req *domain.CreateExpenseRequest) (*domain.Expense, error)
 	GetExpense(ctx context.Context, userID, expenseID primitive.ObjectID) (*domain.Expense, error)
-	GetExpensesByBudget(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.Expense, error)
-	UpdateExpense(ctx context.Context, userID, expenseID primitive.ObjectID, req *domain.CreateExpenseRequest) (*domain.Expense, error)
+	GetExpensesByBudget(ctx context.Context, userID, budgetID primitive.ObjectID, query *domain.ExpenseQuery) (*domain.ExpensePage, error)
+	GetUserExpenses(ctx context.Context, userID primitive.ObjectID, query *domain.ExpenseQuery) (*domain.ExpensePage, error)
+	UpdateExpense(ctx context.Context, userID, expenseID primitive.ObjectID, req *domain.CreateExpenseRequest, version *int64) (*domain.Expense, error)
 	DeleteExpense(ctx context.Context, userID, expenseID primitive.ObjectID) error
+	RecordOccurrence(ctx context.Context, budget *domain.Budget, expense *domain.Expense) error
 }
 
 type expenseService struct {
-	expenseRepo repository.ExpenseRepository
-	budgetRepo  repository.BudgetRepository
-	cache       cache.CacheService
+	uow          repository.UnitOfWork
+	expenseRepo  repository.ExpenseRepository
+	budgetRepo   repository.BudgetRepository
+	categoryRepo repository.CategoryRepository
+	outboxRepo   repository.OutboxRepository
+	rates        ExchangeRateProvider
+	cache        cache.CacheService
 }
 
 func NewExpenseService(
+	uow repository.UnitOfWork,
 	expenseRepo repository.ExpenseRepository,
 	budgetRepo repository.BudgetRepository,
+	categoryRepo repository.CategoryRepository,
+	outboxRepo repository.OutboxRepository,
+	rates ExchangeRateProvider,
 	cache cache.CacheService,
 ) ExpenseService {
 	return &expenseService{
-		expenseRepo: expenseRepo,
-		budgetRepo:  budgetRepo,
-		cache:       cache,
+		uow:          uow,
+		expenseRepo:  expenseRepo,
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+		outboxRepo:   outboxRepo,
+		rates:        rates,
+		cache:        cache,
 	}
 }
 
@@ -46,105 +60,275 @@ func (s *expenseService) CreateExpense(ctx context.Context, userID primitive.Obj
 		return nil, err
 	}
 
-	if budget.UserID != userID {
-		return nil, domain.ErrUnauthorized
+	if err := budget.Authorize(userID, domain.MemberRoleEditor); err != nil {
+		return nil, err
+	}
+
+	category, err := budget.ResolveCategory(req.CategoryID, req.Category)
+	if err != nil {
+		return nil, err
 	}
 
 	expense := &domain.Expense{
-		UserID:      userID,
-		BudgetID:    budgetID,
-		Category:    req.Category,
-		Amount:      req.Amount,
-		Description: req.Description,
-		Date:        req.Date,
+		UserID:           budget.UserID,
+		CreatedBy:        userID,
+		BudgetID:         budgetID,
+		CategoryID:       category.CategoryID,
+		Category:         category.Name,
+		Currency:         budget.Currency,
+		OriginalAmount:   req.Amount,
+		OriginalCurrency: req.Currency,
+		Description:      req.Description,
+		Date:             req.Date,
 	}
 
-	if err := s.expenseRepo.Create(ctx, expense); err != nil {
+	if err := s.convert(ctx, expense); err != nil {
 		return nil, err
 	}
 
-	if err := s.budgetRepo.UpdateSpendAmount(ctx, budgetID, req.Amount); err != nil {
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.Create(ctx, expense); err != nil {
+			return err
+		}
+		if err := s.budgetRepo.UpdateSpendAmount(ctx, budgetID, expense.CategoryID, expense.Amount); err != nil {
+			return err
+		}
+		return s.outboxRepo.Append(ctx, domain.NewExpenseEvent(domain.EventExpenseCreated, expense))
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	s.cache.Delete(ctx, "budget:"+budgetID.Hex())
-	s.cache.Delete(ctx, "budget:user:"+userID.Hex())
+	invalidateBudgetCache(ctx, s.cache, budget)
 
 	return expense, nil
 }
 
 func (s *expenseService) GetExpense(ctx context.Context, userID, expenseID primitive.ObjectID) (*domain.Expense, error) {
-	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	expense, _, err := s.findExpense(ctx, userID, expenseID, domain.MemberRoleViewer)
 	if err != nil {
 		return nil, err
 	}
 
-	if expense.UserID != userID {
-		return nil, domain.ErrUnauthorized
-	}
-
 	return expense, nil
 }
 
-func (s *expenseService) GetExpensesByBudget(ctx context.Context, userID, budgetID primitive.ObjectID) ([]*domain.Expense, error) {
+func (s *expenseService) GetExpensesByBudget(ctx context.Context, userID, budgetID primitive.ObjectID, query *domain.ExpenseQuery) (*domain.ExpensePage, error) {
 	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
 	if err != nil {
 		return nil, err
 	}
 
-	if budget.UserID != userID {
-		return nil, domain.ErrUnauthorized
+	if err := budget.Authorize(userID, domain.MemberRoleViewer); err != nil {
+		return nil, err
 	}
 
-	return s.expenseRepo.FindByBudgetID(ctx, budgetID)
+	query.BudgetID = &budgetID
+	if err := s.expandCategories(ctx, query); err != nil {
+		return nil, err
+	}
+	return s.expenseRepo.Query(ctx, query)
 }
 
-func (s *expenseService) UpdateExpense(ctx context.Context, userID, expenseID primitive.ObjectID, req *domain.CreateExpenseRequest) (*domain.Expense, error) {
-	expense, err := s.GetExpense(ctx, userID, expenseID)
+func (s *expenseService) GetUserExpenses(ctx context.Context, userID primitive.ObjectID, query *domain.ExpenseQuery) (*domain.ExpensePage, error) {
+	budgetIDs, err := accessibleBudgetIDs(ctx, s.budgetRepo, userID)
 	if err != nil {
 		return nil, err
 	}
+	if len(budgetIDs) == 0 {
+		return &domain.ExpensePage{Items: []*domain.Expense{}}, nil
+	}
 
+	query.BudgetID = nil
+	query.BudgetIDs = budgetIDs
+	if err := s.expandCategories(ctx, query); err != nil {
+		return nil, err
+	}
+	return s.expenseRepo.Query(ctx, query)
+}
+
+// expandCategories turns the query's category filter into the category and
+// its subcategories, so filtering on Transport includes Fuel.
+func (s *expenseService) expandCategories(ctx context.Context, query *domain.ExpenseQuery) error {
+	if query.CategoryID == nil {
+		return nil
+	}
+
+	ids, err := categorySubtree(ctx, s.categoryRepo, *query.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	query.Categories = ids
+	return nil
+}
+
+// UpdateExpense replaces the expense with req. When version is set the
+// expense must still be at that version. The update fails with
+// domain.ErrVersionConflict if the expense changes before it is written, so
+// the spent amounts are never adjusted from a stale amount or category.
+func (s *expenseService) UpdateExpense(ctx context.Context, userID, expenseID primitive.ObjectID, req *domain.CreateExpenseRequest, version *int64) (*domain.Expense, error) {
+	expense, budget, err := s.findExpense(ctx, userID, expenseID, domain.MemberRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if version != nil && *version != expense.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	category, err := budget.ResolveCategory(req.CategoryID, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	oldCategory := expense.CategoryID
 	oldAmount := expense.Amount
 
-	expense.Category = req.Category
-	expense.Amount = req.Amount
+	expense.CategoryID = category.CategoryID
+	expense.Category = category.Name
+	expense.Currency = budget.Currency
+	expense.OriginalAmount = req.Amount
+	expense.OriginalCurrency = req.Currency
 	expense.Description = req.Description
 	expense.Date = req.Date
 
-	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+	if err := s.convert(ctx, expense); err != nil {
 		return nil, err
 	}
 
-	diff := req.Amount - oldAmount
-	if diff != 0 {
-		if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, diff); err != nil {
-			return nil, err
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.Update(ctx, expense); err != nil {
+			return err
 		}
+
+		if oldCategory != expense.CategoryID {
+			// Move the whole amount from the old category to the new one.
+			if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, oldCategory, -oldAmount); err != nil {
+				return err
+			}
+			if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, expense.CategoryID, expense.Amount); err != nil {
+				return err
+			}
+		} else if diff := expense.Amount - oldAmount; diff != 0 {
+			if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, expense.CategoryID, diff); err != nil {
+				return err
+			}
+		}
+
+		return s.outboxRepo.Append(ctx, domain.NewExpenseEvent(domain.EventExpenseUpdated, expense))
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	s.cache.Delete(ctx, "budget:"+expense.BudgetID.Hex())
-	s.cache.Delete(ctx, "budgets:user:"+userID.Hex())
+	invalidateBudgetCache(ctx, s.cache, budget)
 
 	return expense, nil
 }
 
 func (s *expenseService) DeleteExpense(ctx context.Context, userID, expenseID primitive.ObjectID) error {
-	expense, err := s.GetExpense(ctx, userID, expenseID)
+	_, budget, err := s.findExpense(ctx, userID, expenseID, domain.MemberRoleEditor)
 	if err != nil {
 		return err
 	}
 
-	if err := s.expenseRepo.Delete(ctx, expenseID); err != nil {
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Read the expense again inside the transaction, so the amount taken
+		// off the budget is the one deleted even if an update committed after
+		// the read above. A concurrent write now aborts one of the two.
+		expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.expenseRepo.Delete(ctx, expenseID); err != nil {
+			return err
+		}
+		if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, expense.CategoryID, -expense.Amount); err != nil {
+			return err
+		}
+		return s.outboxRepo.Append(ctx, domain.NewExpenseEvent(domain.EventExpenseDeleted, expense))
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return nil
+}
+
+// RecordOccurrence stores an expense generated from a recurring expense and
+// charges it to budget. It returns domain.ErrOccurrenceExists, without
+// touching the budget, when the occurrence was already recorded.
+func (s *expenseService) RecordOccurrence(ctx context.Context, budget *domain.Budget, expense *domain.Expense) error {
+	if err := s.convert(ctx, expense); err != nil {
 		return err
 	}
 
-	if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, -expense.Amount); err != nil {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.expenseRepo.Create(ctx, expense); err != nil {
+			return err
+		}
+		if err := s.budgetRepo.UpdateSpendAmount(ctx, expense.BudgetID, expense.CategoryID, expense.Amount); err != nil {
+			return err
+		}
+		return s.outboxRepo.Append(ctx, domain.NewExpenseEvent(domain.EventExpenseCreated, expense))
+	})
+	if err != nil {
 		return err
 	}
 
-	s.cache.Delete(ctx, "budget:"+expense.BudgetID.Hex())
-	s.cache.Delete(ctx, "budgets:user:"+userID.Hex())
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return nil
+}
+
+// findExpense loads an expense with its budget and checks that the user holds
+// at least role on the budget.
+func (s *expenseService) findExpense(ctx context.Context, userID, expenseID primitive.ObjectID, role domain.MemberRole) (*domain.Expense, *domain.Budget, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	budget, err := s.budgetRepo.FindByID(ctx, expense.BudgetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := budget.Authorize(userID, role); err != nil {
+		return nil, nil, err
+	}
+
+	return expense, budget, nil
+}
+
+// convert sets the expense amount in the budget currency from the amount
+// originally paid, using the exchange rate for the expense date. An expense
+// without an original currency is taken to be in the budget currency.
+func (s *expenseService) convert(ctx context.Context, expense *domain.Expense) error {
+	if expense.OriginalCurrency == "" {
+		expense.OriginalCurrency = expense.Currency
+	}
+
+	rate, err := s.rates.Rate(ctx, expense.OriginalCurrency, expense.Currency, expense.Date)
+	if err != nil {
+		return err
+	}
+
+	amount, err := expense.OriginalAmount.Convert(rate)
+	if err != nil {
+		return err
+	}
+
+	exchangeRate, err := domain.DecimalFromRat(rate)
+	if err != nil {
+		return err
+	}
 
+	expense.Amount = amount
+	expense.ExchangeRate = exchangeRate
 	return nil
 }
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// forecastHistoryPeriods is how many previous periods of a budget are
+	// used to learn its usual spending.
+	forecastHistoryPeriods = 3
+	// forecastDefaultSpread is the relative width of the confidence band
+	// around the remaining spend when there is too little history to measure
+	// how much spending varies between periods.
+	forecastDefaultSpread = 0.5
+	forecastMinSpread     = 0.1
+)
+
+type ForecastService interface {
+	GetForecast(ctx context.Context, userID, budgetID primitive.ObjectID) (*domain.BudgetForecast, error)
+}
+
+type forecastService struct {
+	budgetRepo    repository.BudgetRepository
+	expenseRepo   repository.ExpenseRepository
+	recurringRepo repository.RecurringExpenseRepository
+	rates         ExchangeRateProvider
+}
+
+func NewForecastService(
+	budgetRepo repository.BudgetRepository,
+	expenseRepo repository.ExpenseRepository,
+	recurringRepo repository.RecurringExpenseRepository,
+	rates ExchangeRateProvider,
+) ForecastService {
+	return &forecastService{
+		budgetRepo:    budgetRepo,
+		expenseRepo:   expenseRepo,
+		recurringRepo: recurringRepo,
+		rates:         rates,
+	}
+}
+
+// scheduledCharge is a recurring expense occurrence that has not been
+// recorded yet.
+type scheduledCharge struct {
+	CategoryID primitive.ObjectID
+	Date       time.Time
+	Amount     domain.Money
+}
+
+// GetForecast projects the budget's spending to the end of its period.
+// Recurring expenses are forecast from their schedule. The rest of the spend
+// is projected from the burn rate so far, blended with the average of
+// previous periods; history dominates early in the period and the burn rate
+// takes over as the period progresses.
+func (s *forecastService) GetForecast(ctx context.Context, userID, budgetID primitive.ObjectID) (*domain.BudgetForecast, error) {
+	budget, err := s.budgetRepo.FindByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := budget.Authorize(userID, domain.MemberRoleViewer); err != nil {
+		return nil, err
+	}
+
+	expenses, err := s.expenseRepo.FindByBudgetID(ctx, budget.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming, err := s.upcomingCharges(ctx, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.history(ctx, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	return forecastBudget(budget, time.Now(), discretionarySpend(expenses), upcoming, history), nil
+}
+
+// upcomingCharges returns the occurrences of the owner's recurring expenses
+// that fall in the budget period and have not been recorded yet, converted to
+// the budget currency.
+func (s *forecastService) upcomingCharges(ctx context.Context, budget *domain.Budget) ([]scheduledCharge, error) {
+	recurring, err := s.recurringRepo.FindByUserID(ctx, budget.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var charges []scheduledCharge
+	for _, item := range recurring {
+		if !item.IsActive || budget.FindCategory(item.CategoryID) == nil {
+			continue
+		}
+
+		currency := item.Currency
+		if currency == "" {
+			currency = budget.Currency
+		}
+
+		for n := item.OccurrenceCount; ; n++ {
+			date := item.Rule.Occurrence(item.StartDate, n)
+			if date == nil || !date.Before(budget.EndDate) {
+				break
+			}
+			if date.Before(budget.StartDate) {
+				continue
+			}
+
+			rate, err := s.rates.Rate(ctx, currency, budget.Currency, *date)
+			if err != nil {
+				return nil, err
+			}
+			amount, err := item.Amount.Convert(rate)
+			if err != nil {
+				return nil, err
+			}
+
+			charges = append(charges, scheduledCharge{
+				CategoryID: item.CategoryID,
+				Date:       *date,
+				Amount:     amount,
+			})
+		}
+	}
+
+	return charges, nil
+}
+
+// history returns the discretionary spend per category of up to
+// forecastHistoryPeriods previous periods, scaled to the length of the
+// current period. Periods kept in another currency are skipped.
+func (s *forecastService) history(ctx context.Context, budget *domain.Budget) ([]map[primitive.ObjectID]domain.Money, error) {
+	length := budget.EndDate.Sub(budget.StartDate)
+
+	var periods []map[primitive.ObjectID]domain.Money
+	previousID := budget.PreviousBudgetID
+	for previousID != nil && len(periods) < forecastHistoryPeriods {
+		previous, err := s.budgetRepo.FindByID(ctx, *previousID)
+		if err != nil {
+			if err == domain.ErrBudgetNotFound {
+				break
+			}
+			return nil, err
+		}
+		previousID = previous.PreviousBudgetID
+
+		if previous.Currency != budget.Currency {
+			continue
+		}
+
+		expenses, err := s.expenseRepo.FindByBudgetID(ctx, previous.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		spend := discretionarySpend(expenses)
+		scale := float64(length) / float64(previous.EndDate.Sub(previous.StartDate))
+		for category, amount := range spend {
+			spend[category] = domain.Money(math.Round(float64(amount) * scale))
+		}
+		for _, category := range previous.Categories {
+			if _, ok := spend[category.CategoryID]; !ok {
+				spend[category.CategoryID] = 0
+			}
+		}
+		periods = append(periods, spend)
+	}
+
+	return periods, nil
+}
+
+// discretionarySpend sums the expenses that were not generated by a recurring
+// expense, per category.
+func discretionarySpend(expenses []*domain.Expense) map[primitive.ObjectID]domain.Money {
+	spend := make(map[primitive.ObjectID]domain.Money)
+	for _, expense := range expenses {
+		if expense.RecurringExpenseID != nil {
+			continue
+		}
+		spend[expense.CategoryID] += expense.Amount
+	}
+	return spend
+}
+
+func forecastBudget(budget *domain.Budget, now time.Time, discretionary map[primitive.ObjectID]domain.Money, upcoming []scheduledCharge, history []map[primitive.ObjectID]domain.Money) *domain.BudgetForecast {
+	forecast := &domain.BudgetForecast{
+		BudgetID:       budget.ID,
+		Currency:       budget.Currency,
+		AsOf:           now,
+		StartDate:      budget.StartDate,
+		EndDate:        budget.EndDate,
+		DaysTotal:      int(math.Ceil(budget.EndDate.Sub(budget.StartDate).Hours() / 24)),
+		HistoryPeriods: len(history),
+		Categories:     make([]domain.CategoryForecast, len(budget.Categories)),
+	}
+	if now.After(budget.StartDate) {
+		forecast.DaysElapsed = int(math.Min(now.Sub(budget.StartDate).Hours()/24, float64(forecast.DaysTotal)))
+	}
+
+	var totalDiscretionary domain.Money
+	totalHistory := make([]domain.Money, len(history))
+	for i, category := range budget.Categories {
+		var categoryHistory []domain.Money
+		for j, period := range history {
+			if amount, ok := period[category.CategoryID]; ok {
+				categoryHistory = append(categoryHistory, amount)
+				totalHistory[j] += amount
+			}
+		}
+
+		var charges []scheduledCharge
+		for _, charge := range upcoming {
+			if charge.CategoryID == category.CategoryID {
+				charges = append(charges, charge)
+			}
+		}
+
+		forecast.Categories[i] = domain.CategoryForecast{
+			CategoryID:   category.CategoryID,
+			Name:         category.Name,
+			ForecastLine: projectSpend(budget, now, category.Amount, category.SpentAmount, discretionary[category.CategoryID], charges, categoryHistory),
+		}
+		totalDiscretionary += discretionary[category.CategoryID]
+	}
+
+	forecast.Total = projectSpend(budget, now, budget.TotalAmount, budget.SpentAmount, totalDiscretionary, upcoming, totalHistory)
+
+	return forecast
+}
+
+// projectSpend forecasts one line of the budget. spent is everything charged
+// so far and discretionary the part of it not generated by recurring
+// expenses; history holds the discretionary spend of previous periods.
+func projectSpend(budget *domain.Budget, now time.Time, limit, spent, discretionary domain.Money, upcoming []scheduledCharge, history []domain.Money) domain.ForecastLine {
+	line := domain.ForecastLine{
+		Limit: limit,
+		Spent: spent,
+	}
+	for _, charge := range upcoming {
+		line.UpcomingRecurring += charge.Amount
+	}
+
+	elapsed := now.Sub(budget.StartDate).Hours() / budget.EndDate.Sub(budget.StartDate).Hours()
+	elapsed = math.Max(0, math.Min(1, elapsed))
+
+	expected := float64(discretionary)
+	if elapsed > 0 {
+		expected /= elapsed
+	}
+	if len(history) > 0 {
+		expected = elapsed*expected + (1-elapsed)*mean(history)
+	}
+
+	remaining := math.Max(0, expected-float64(discretionary))
+	if elapsed >= 1 {
+		remaining = 0
+	}
+
+	spread := forecastDefaultSpread
+	if len(history) >= 2 {
+		spread = math.Max(forecastMinSpread, math.Min(1, variation(history)))
+	}
+	margin := remaining * spread
+
+	base := float64(spent + line.UpcomingRecurring)
+	line.Projected = domain.Money(math.Round(base + remaining))
+	line.Low = domain.Money(math.Round(base + remaining - margin))
+	line.High = domain.Money(math.Round(base + remaining + margin))
+
+	from := now
+	if from.Before(budget.StartDate) {
+		from = budget.StartDate
+	}
+	line.OverspendDate = overspendDate(limit, spent, remaining, upcoming, from, budget.EndDate)
+
+	return line
+}
+
+// overspendDate walks the rest of the period a day at a time, spending
+// remaining evenly and recurring charges on their dates, and returns the
+// first day on which spending passes limit. Days run from midnight, so a
+// charge is counted on its own date whatever the time of from.
+func overspendDate(limit, spent domain.Money, remaining float64, upcoming []scheduledCharge, from, end time.Time) *time.Time {
+	if spent > limit {
+		day := startOfDay(from)
+		return &day
+	}
+
+	days := end.Sub(from).Hours() / 24
+	if days <= 0 {
+		return nil
+	}
+	rate := remaining / days
+
+	for day := startOfDay(from); day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		if next.After(end) {
+			next = end
+		}
+
+		total := float64(spent) + rate*next.Sub(from).Hours()/24
+		for _, charge := range upcoming {
+			if charge.Date.Before(next) {
+				total += float64(charge.Amount)
+			}
+		}
+
+		if total > float64(limit) {
+			return &day
+		}
+	}
+
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func mean(values []domain.Money) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += float64(value)
+	}
+	return sum / float64(len(values))
+}
+
+// variation returns the coefficient of variation of values, the standard
+// deviation relative to the mean.
+func variation(values []domain.Money) float64 {
+	avg := mean(values)
+	if avg == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		diff := float64(value) - avg
+		sum += diff * diff
+	}
+	return math.Sqrt(sum/float64(len(values))) / avg
+}
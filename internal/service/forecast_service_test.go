@@ -0,0 +1,225 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestForecastBudget(t *testing.T) {
+	food := primitive.NewObjectID()
+	start := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	midway := time.Date(2025, time.April, 16, 0, 0, 0, 0, time.UTC)
+	date := func(day int) *time.Time {
+		d := time.Date(2025, time.April, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		spent         domain.Money
+		discretionary domain.Money
+		upcoming      []domain.Money
+		history       []domain.Money
+		want          domain.ForecastLine
+		wantElapsed   int
+	}{
+		{
+			name:          "pace without history",
+			now:           midway,
+			spent:         30000,
+			discretionary: 30000,
+			want:          domain.ForecastLine{Projected: 60000, Low: 45000, High: 75000},
+			wantElapsed:   15,
+		},
+		{
+			name:          "history pulls the pace towards the usual spend",
+			now:           midway,
+			spent:         30000,
+			discretionary: 30000,
+			history:       []domain.Money{30000, 50000},
+			want:          domain.ForecastLine{Projected: 50000, Low: 45000, High: 55000},
+			wantElapsed:   15,
+		},
+		{
+			name:          "steady history keeps a minimum band",
+			now:           midway,
+			spent:         30000,
+			discretionary: 30000,
+			history:       []domain.Money{40000, 40000},
+			want:          domain.ForecastLine{Projected: 50000, Low: 48000, High: 52000},
+			wantElapsed:   15,
+		},
+		{
+			name:          "recurring charges are not extrapolated",
+			now:           midway,
+			spent:         50000,
+			discretionary: 20000,
+			want:          domain.ForecastLine{Projected: 70000, Low: 60000, High: 80000},
+			wantElapsed:   15,
+		},
+		{
+			name:          "upcoming recurring charge overspends on a later day",
+			now:           midway,
+			spent:         30000,
+			discretionary: 30000,
+			upcoming:      []domain.Money{50000},
+			want: domain.ForecastLine{
+				UpcomingRecurring: 50000,
+				Projected:         110000,
+				Low:               95000,
+				High:              125000,
+				OverspendDate:     date(26),
+			},
+			wantElapsed: 15,
+		},
+		{
+			name:          "already overspent",
+			now:           midway,
+			spent:         110000,
+			discretionary: 110000,
+			want:          domain.ForecastLine{Projected: 220000, Low: 165000, High: 275000, OverspendDate: date(16)},
+			wantElapsed:   15,
+		},
+		{
+			name:    "before the period starts history is the forecast",
+			now:     start.AddDate(0, 0, -5),
+			history: []domain.Money{40000, 40000},
+			want:    domain.ForecastLine{Projected: 40000, Low: 36000, High: 44000},
+		},
+		{
+			name:          "after the period ends nothing remains",
+			now:           end.AddDate(0, 0, 2),
+			spent:         80000,
+			discretionary: 80000,
+			want:          domain.ForecastLine{Projected: 80000, Low: 80000, High: 80000},
+			wantElapsed:   30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &domain.Budget{
+				ID:        primitive.NewObjectID(),
+				StartDate: start,
+				EndDate:   end,
+				Categories: []domain.BudgetCategory{
+					{CategoryID: food, Name: "Food", Amount: 100000, SpentAmount: tt.spent},
+				},
+				TotalAmount: 100000,
+				SpentAmount: tt.spent,
+			}
+			var upcoming []scheduledCharge
+			for _, amount := range tt.upcoming {
+				upcoming = append(upcoming, scheduledCharge{CategoryID: food, Date: *date(20), Amount: amount})
+			}
+			history := make([]map[primitive.ObjectID]domain.Money, len(tt.history))
+			for i, amount := range tt.history {
+				history[i] = map[primitive.ObjectID]domain.Money{food: amount}
+			}
+			discretionary := map[primitive.ObjectID]domain.Money{food: tt.discretionary}
+
+			forecast := forecastBudget(budget, tt.now, discretionary, upcoming, history)
+
+			want := tt.want
+			want.Limit = 100000
+			want.Spent = tt.spent
+			assertForecastLine(t, "category", forecast.Categories[0].ForecastLine, want)
+			assertForecastLine(t, "total", forecast.Total, want)
+			if forecast.DaysTotal != 30 || forecast.DaysElapsed != tt.wantElapsed {
+				t.Errorf("days = %d/%d, want %d/30", forecast.DaysElapsed, forecast.DaysTotal, tt.wantElapsed)
+			}
+			if forecast.HistoryPeriods != len(tt.history) {
+				t.Errorf("history periods = %d, want %d", forecast.HistoryPeriods, len(tt.history))
+			}
+		})
+	}
+}
+
+func assertForecastLine(t *testing.T, label string, got, want domain.ForecastLine) {
+	t.Helper()
+	if (got.OverspendDate == nil) != (want.OverspendDate == nil) ||
+		got.OverspendDate != nil && !got.OverspendDate.Equal(*want.OverspendDate) {
+		t.Errorf("%s overspend date = %v, want %v", label, got.OverspendDate, want.OverspendDate)
+	}
+	got.OverspendDate, want.OverspendDate = nil, nil
+	if got != want {
+		t.Errorf("%s = %+v, want %+v", label, got, want)
+	}
+}
+
+func TestOverspendDate(t *testing.T) {
+	from := time.Date(2025, time.April, 16, 9, 30, 0, 0, time.UTC)
+	end := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) *time.Time {
+		t := time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		limit     domain.Money
+		spent     domain.Money
+		remaining float64
+		upcoming  []scheduledCharge
+		from      time.Time
+		want      *time.Time
+	}{
+		{
+			name:      "stays within the limit",
+			limit:     100000,
+			spent:     50000,
+			remaining: 40000,
+			from:      from,
+		},
+		{
+			name:  "reaching the limit exactly is not overspending",
+			limit: 100000,
+			spent: 100000,
+			from:  from,
+		},
+		{
+			name:  "already over the limit is reported today",
+			limit: 100000,
+			spent: 100001,
+			from:  from,
+			want:  day(time.April, 16),
+		},
+		{
+			name:      "steady spending crosses the limit",
+			limit:     100000,
+			spent:     70000,
+			remaining: 60000,
+			from:      time.Date(2025, time.April, 16, 0, 0, 0, 0, time.UTC),
+			want:      day(time.April, 23),
+		},
+		{
+			name:     "recurring charge crosses the limit on its date",
+			limit:    100000,
+			spent:    70000,
+			upcoming: []scheduledCharge{{Date: time.Date(2025, time.April, 28, 8, 0, 0, 0, time.UTC), Amount: 40000}},
+			from:     from,
+			want:     day(time.April, 28),
+		},
+		{
+			name:      "period already over",
+			limit:     100000,
+			spent:     50000,
+			remaining: 90000,
+			from:      end,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := overspendDate(tt.limit, tt.spent, tt.remaining, tt.upcoming, tt.from, end)
+			if (got == nil) != (tt.want == nil) || got != nil && !got.Equal(*tt.want) {
+				t.Errorf("overspend date = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// goalReminderInterval is how long to wait before reminding a user again
+// about a goal that is still behind schedule.
+const goalReminderInterval = 7 * 24 * time.Hour
+
+type GoalService interface {
+	CreateGoal(ctx context.Context, userID primitive.ObjectID, req *domain.CreateGoalRequest) (*domain.Goal, error)
+	GetGoal(ctx context.Context, userID, goalID primitive.ObjectID) (*domain.Goal, error)
+	GetUserGoals(ctx context.Context, userID primitive.ObjectID) ([]*domain.Goal, error)
+	UpdateGoal(ctx context.Context, userID, goalID primitive.ObjectID, req *domain.UpdateGoalRequest) (*domain.Goal, error)
+	DeleteGoal(ctx context.Context, userID, goalID primitive.ObjectID) error
+	AddContribution(ctx context.Context, userID, goalID primitive.ObjectID, req *domain.CreateContributionRequest) (*domain.GoalContribution, error)
+	GetContributions(ctx context.Context, userID, goalID primitive.ObjectID) ([]*domain.GoalContribution, error)
+	DeleteContribution(ctx context.Context, userID, goalID, contributionID primitive.ObjectID) error
+	NotifyBehindSchedule(ctx context.Context, now time.Time) (int, error)
+}
+
+type goalService struct {
+	uow             repository.UnitOfWork
+	goalRepo        repository.GoalRepository
+	userRepo        repository.UserRepository
+	categoryService CategoryService
+	emailService    EmailService
+	defaultCurrency domain.Currency
+}
+
+func NewGoalService(
+	uow repository.UnitOfWork,
+	goalRepo repository.GoalRepository,
+	userRepo repository.UserRepository,
+	categoryService CategoryService,
+	emailService EmailService,
+	defaultCurrency domain.Currency,
+) GoalService {
+	return &goalService{
+		uow:             uow,
+		goalRepo:        goalRepo,
+		userRepo:        userRepo,
+		categoryService: categoryService,
+		emailService:    emailService,
+		defaultCurrency: defaultCurrency,
+	}
+}
+
+func (s *goalService) CreateGoal(ctx context.Context, userID primitive.ObjectID, req *domain.CreateGoalRequest) (*domain.Goal, error) {
+	startDate := time.Now()
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+
+	if !req.TargetDate.After(startDate) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	goal := &domain.Goal{
+		UserID:       userID,
+		Name:         req.Name,
+		Currency:     req.Currency,
+		TargetAmount: req.TargetAmount,
+		StartDate:    startDate,
+		TargetDate:   req.TargetDate,
+	}
+	if goal.Currency == "" {
+		goal.Currency = s.defaultCurrency
+	}
+
+	if err := s.setCategory(ctx, goal, req.CategoryID, req.Category); err != nil {
+		return nil, err
+	}
+
+	if err := s.goalRepo.Create(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	return withProgress(goal), nil
+}
+
+func (s *goalService) GetGoal(ctx context.Context, userID, goalID primitive.ObjectID) (*domain.Goal, error) {
+	goal, err := s.goalRepo.FindByID(ctx, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if goal.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return withProgress(goal), nil
+}
+
+func (s *goalService) GetUserGoals(ctx context.Context, userID primitive.ObjectID) ([]*domain.Goal, error) {
+	goals, err := s.goalRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, goal := range goals {
+		withProgress(goal)
+	}
+
+	return goals, nil
+}
+
+func (s *goalService) UpdateGoal(ctx context.Context, userID, goalID primitive.ObjectID, req *domain.UpdateGoalRequest) (*domain.Goal, error) {
+	goal, err := s.GetGoal(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !req.TargetDate.After(goal.StartDate) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := s.setCategory(ctx, goal, req.CategoryID, req.Category); err != nil {
+		return nil, err
+	}
+	goal.Name = req.Name
+	goal.TargetAmount = req.TargetAmount
+	goal.TargetDate = req.TargetDate
+
+	if err := s.goalRepo.Update(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	return withProgress(goal), nil
+}
+
+// setCategory points the goal at the catalog category named by categoryID or
+// name, or clears it when both are empty. Keeping the current category is
+// allowed even once it has been archived.
+func (s *goalService) setCategory(ctx context.Context, goal *domain.Goal, categoryID, name string) error {
+	if categoryID == "" && name == "" {
+		goal.CategoryID = nil
+		goal.Category = ""
+		return nil
+	}
+	if goal.CategoryID != nil && categoryID == goal.CategoryID.Hex() {
+		return nil
+	}
+
+	var id primitive.ObjectID
+	if categoryID != "" {
+		parsed, err := primitive.ObjectIDFromHex(categoryID)
+		if err != nil {
+			return domain.ErrInvalidInput
+		}
+		id = parsed
+	}
+
+	category, err := s.categoryService.ResolveCategory(ctx, goal.UserID, id, name)
+	if err != nil {
+		return err
+	}
+
+	goal.CategoryID = &category.ID
+	goal.Category = category.Name
+	return nil
+}
+
+func (s *goalService) DeleteGoal(ctx context.Context, userID, goalID primitive.ObjectID) error {
+	if _, err := s.GetGoal(ctx, userID, goalID); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.goalRepo.DeleteContributions(ctx, goalID); err != nil {
+			return err
+		}
+		return s.goalRepo.Delete(ctx, goalID)
+	})
+}
+
+func (s *goalService) AddContribution(ctx context.Context, userID, goalID primitive.ObjectID, req *domain.CreateContributionRequest) (*domain.GoalContribution, error) {
+	if _, err := s.GetGoal(ctx, userID, goalID); err != nil {
+		return nil, err
+	}
+
+	contribution := &domain.GoalContribution{
+		GoalID: goalID,
+		UserID: userID,
+		Amount: req.Amount,
+		Note:   req.Note,
+		Date:   time.Now(),
+	}
+	if req.Date != nil {
+		contribution.Date = *req.Date
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.goalRepo.CreateContribution(ctx, contribution); err != nil {
+			return err
+		}
+		return s.goalRepo.AddSaved(ctx, goalID, contribution.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return contribution, nil
+}
+
+func (s *goalService) GetContributions(ctx context.Context, userID, goalID primitive.ObjectID) ([]*domain.GoalContribution, error) {
+	if _, err := s.GetGoal(ctx, userID, goalID); err != nil {
+		return nil, err
+	}
+
+	return s.goalRepo.FindContributions(ctx, goalID)
+}
+
+func (s *goalService) DeleteContribution(ctx context.Context, userID, goalID, contributionID primitive.ObjectID) error {
+	if _, err := s.GetGoal(ctx, userID, goalID); err != nil {
+		return err
+	}
+
+	contribution, err := s.goalRepo.FindContribution(ctx, contributionID)
+	if err != nil {
+		return err
+	}
+
+	if contribution.GoalID != goalID {
+		return domain.ErrContributionNotFound
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.goalRepo.DeleteContribution(ctx, contributionID); err != nil {
+			return err
+		}
+		return s.goalRepo.AddSaved(ctx, goalID, -contribution.Amount)
+	})
+}
+
+// NotifyBehindSchedule emails the owner of every open goal whose savings have
+// fallen behind its schedule, at most once per goalReminderInterval. It
+// returns the number of reminders sent.
+func (s *goalService) NotifyBehindSchedule(ctx context.Context, now time.Time) (int, error) {
+	goals, err := s.goalRepo.FindOpen(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, goal := range goals {
+		progress := goal.ComputeProgress(now)
+		if !progress.IsBehind {
+			continue
+		}
+		if goal.BehindNotifiedAt != nil && now.Sub(*goal.BehindNotifiedAt) < goalReminderInterval {
+			continue
+		}
+
+		user, err := s.userRepo.FindByID(ctx, goal.UserID)
+		if err != nil {
+			log.Printf("Goal %s: failed to load user: %v", goal.ID.Hex(), err)
+			continue
+		}
+
+		// Claim the reminder before sending it so that concurrent runs
+		// send it once.
+		claimed, err := s.goalRepo.ClaimBehindReminder(ctx, goal.ID, goal.BehindNotifiedAt, now)
+		if err != nil {
+			log.Printf("Goal %s: failed to record reminder: %v", goal.ID.Hex(), err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.emailService.SendGoalBehindScheduleEmail(
+			ctx,
+			user.Email,
+			user.FirstName,
+			goal.Name,
+			string(goal.Currency),
+			goal.SavedAmount,
+			progress.ExpectedAmount,
+			progress.RequiredMonthly,
+		); err != nil {
+			log.Printf("Goal %s: failed to send reminder: %v", goal.ID.Hex(), err)
+			if err := s.goalRepo.ReleaseBehindReminder(ctx, goal.ID, now, goal.BehindNotifiedAt); err != nil {
+				log.Printf("Goal %s: failed to release reminder: %v", goal.ID.Hex(), err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func withProgress(goal *domain.Goal) *domain.Goal {
+	progress := goal.ComputeProgress(time.Now())
+	goal.Progress = &progress
+	return goal
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationService interface {
+	GetInbox(ctx context.Context, userID primitive.ObjectID, query *domain.NotificationQuery) (*domain.NotificationPage, error)
+	GetUnreadCount(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	MarkNotification(ctx context.Context, userID, notificationID primitive.ObjectID, read bool) (*domain.AlertNotification, error)
+	MarkNotifications(ctx context.Context, userID primitive.ObjectID, req *domain.MarkNotificationsRequest, read bool) (int64, error)
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *notificationService) GetInbox(ctx context.Context, userID primitive.ObjectID, query *domain.NotificationQuery) (*domain.NotificationPage, error) {
+	query.UserID = userID
+	return s.notificationRepo.Query(ctx, query)
+}
+
+func (s *notificationService) GetUnreadCount(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+func (s *notificationService) MarkNotification(ctx context.Context, userID, notificationID primitive.ObjectID, read bool) (*domain.AlertNotification, error) {
+	notification, err := s.notificationRepo.FindByID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if notification.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	if _, err := s.notificationRepo.SetRead(ctx, userID, []primitive.ObjectID{notificationID}, read); err != nil {
+		return nil, err
+	}
+
+	if !read {
+		notification.ReadAt = nil
+	} else if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+	}
+
+	return notification, nil
+}
+
+// MarkNotifications marks several notifications at once and returns how many
+// changed. IDs of notifications that are not in the user's inbox are skipped.
+func (s *notificationService) MarkNotifications(ctx context.Context, userID primitive.ObjectID, req *domain.MarkNotificationsRequest, read bool) (int64, error) {
+	if req.All {
+		return s.notificationRepo.SetRead(ctx, userID, nil, read)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(req.IDs))
+	for _, rawID := range req.IDs {
+		id, err := primitive.ObjectIDFromHex(rawID)
+		if err != nil {
+			return 0, domain.ErrInvalidInput
+		}
+		ids = append(ids, id)
+	}
+
+	return s.notificationRepo.SetRead(ctx, userID, ids, read)
+}
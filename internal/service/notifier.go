@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+)
+
+const (
+	webhookEventHeader     = "X-Budget-Tracker-Event"
+	webhookTimestampHeader = "X-Budget-Tracker-Timestamp"
+	webhookSignatureHeader = "X-Budget-Tracker-Signature"
+)
+
+// Notifier delivers a triggered alert to a user over one kind of channel.
+type Notifier interface {
+	Notify(ctx context.Context, user *domain.User, channel domain.AlertChannel, message *domain.AlertMessage) error
+}
+
+// NewNotifiers returns a notifier for every channel an alert can select.
+func NewNotifiers(cfg *config.Config, emailService EmailService) map[domain.NotificationChannel]Notifier {
+	client := newWebhookClient(cfg.Notification.WebhookTimeout)
+
+	return map[domain.NotificationChannel]Notifier{
+		domain.NotificationChannelEmail:   &emailNotifier{emailService: emailService},
+		domain.NotificationChannelWebhook: &webhookNotifier{client: client},
+		domain.NotificationChannelSlack:   &slackNotifier{client: client},
+		domain.NotificationChannelInApp:   &inAppNotifier{},
+	}
+}
+
+// errForbiddenAddress is returned for webhook hosts that resolve to an
+// address inside our own network.
+var errForbiddenAddress = errors.New("webhook address is not publicly routable")
+
+// sharedAddressSpace is the carrier-grade NAT range, which net.IP does not
+// class as private.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// newWebhookClient returns the client for user-supplied webhook URLs. Every
+// connection, including one made for a redirect, is checked after DNS
+// resolution and refused when it would reach a loopback, private, link-local
+// or otherwise internal address, so a channel cannot be pointed at the
+// worker's own network or the cloud metadata service. Redirects are not
+// followed at all, and no proxy is used so the check sees the real peer.
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil || !isPublicAddress(ip) {
+				return errForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	ip := net.IP(addr.AsSlice())
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+type emailNotifier struct {
+	emailService EmailService
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, user *domain.User, channel domain.AlertChannel, message *domain.AlertMessage) error {
+	name := message.BudgetName
+	if message.CategoryName != "" {
+		name = fmt.Sprintf("%s (%s)", message.BudgetName, message.CategoryName)
+	}
+	return n.emailService.SendBudgetAlertEmail(ctx, user.Email, user.FirstName, name, message.PercentageSpent)
+}
+
+// webhookNotifier posts the alert message as JSON. The signature header is
+// "sha256=" followed by the hex HMAC-SHA256 of the timestamp header, a dot and
+// the body, keyed with the channel secret. Receivers should reject stale
+// timestamps to stop replays.
+type webhookNotifier struct {
+	client *http.Client
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, user *domain.User, channel domain.AlertChannel, message *domain.AlertMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, message.Event)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(channel.Secret, timestamp, body))
+
+	return send(n.client, req)
+}
+
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// slackNotifier posts to a Slack or Mattermost incoming webhook, both of which
+// accept a JSON object with a text field.
+type slackNotifier struct {
+	client *http.Client
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, user *domain.User, channel domain.AlertChannel, message *domain.AlertMessage) error {
+	body, err := json.Marshal(map[string]string{"text": message.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return send(n.client, req)
+}
+
+// inAppNotifier delivers to the user's inbox, which is the alert_notifications
+// collection itself, so recording the attempt is all there is to do.
+type inAppNotifier struct{}
+
+func (n *inAppNotifier) Notify(ctx context.Context, user *domain.User, channel domain.AlertChannel, message *domain.AlertMessage) error {
+	return nil
+}
+
+// send performs the request and treats any status outside 2xx as a failed
+// delivery.
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s responded with %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
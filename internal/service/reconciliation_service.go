@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const reconciliationBatchSize = 200
+
+type ReconciliationService interface {
+	Reconcile(ctx context.Context, trigger domain.ReconciliationTrigger, repair bool) (*domain.ReconciliationReport, error)
+	GetRecentReports(ctx context.Context, limit int) ([]*domain.ReconciliationReport, error)
+}
+
+type reconciliationService struct {
+	uow                repository.UnitOfWork
+	budgetRepo         repository.BudgetRepository
+	expenseRepo        repository.ExpenseRepository
+	reconciliationRepo repository.ReconciliationRepository
+	cache              cache.CacheService
+}
+
+func NewReconciliationService(
+	uow repository.UnitOfWork,
+	budgetRepo repository.BudgetRepository,
+	expenseRepo repository.ExpenseRepository,
+	reconciliationRepo repository.ReconciliationRepository,
+	cache cache.CacheService,
+) ReconciliationService {
+	return &reconciliationService{
+		uow:                uow,
+		budgetRepo:         budgetRepo,
+		expenseRepo:        expenseRepo,
+		reconciliationRepo: reconciliationRepo,
+		cache:              cache,
+	}
+}
+
+// Reconcile recomputes the spent amounts of every budget from its expenses
+// and reports the budgets whose stored totals disagree. With repair set, the
+// stored totals are overwritten with the recomputed ones. The report is
+// persisted so that drift showing up in consecutive runs can be flagged.
+func (s *reconciliationService) Reconcile(ctx context.Context, trigger domain.ReconciliationTrigger, repair bool) (*domain.ReconciliationReport, error) {
+	report := &domain.ReconciliationReport{
+		Trigger:   trigger,
+		Repair:    repair,
+		Drifts:    []domain.BudgetDrift{},
+		StartedAt: time.Now(),
+	}
+
+	previouslyDrifted := make(map[primitive.ObjectID]bool)
+	previous, err := s.reconciliationRepo.FindLatest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		for _, drift := range previous.Drifts {
+			previouslyDrifted[drift.BudgetID] = true
+		}
+	}
+
+	afterID := primitive.NilObjectID
+	for {
+		budgets, err := s.budgetRepo.FindPage(ctx, afterID, reconciliationBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(budgets) == 0 {
+			break
+		}
+
+		budgetIDs := make([]primitive.ObjectID, len(budgets))
+		for i, budget := range budgets {
+			budgetIDs[i] = budget.ID
+		}
+
+		spend, err := s.expenseRepo.SumSpendByBudget(ctx, budgetIDs)
+		if err != nil {
+			return nil, err
+		}
+		actual := groupSpendByBudget(spend)
+
+		for _, budget := range budgets {
+			drift := compareSpend(budget, actual[budget.ID])
+			if drift == nil {
+				continue
+			}
+
+			drift.Persistent = previouslyDrifted[budget.ID]
+			if repair {
+				if err := s.repair(ctx, budget.ID); err != nil {
+					drift.RepairErr = err.Error()
+				} else {
+					drift.Repaired = true
+				}
+			}
+
+			report.Drifts = append(report.Drifts, *drift)
+			if drift.Persistent {
+				report.PersistentCount++
+				log.Printf("Reconciliation: budget %s has drifted in consecutive runs (stored %s, actual %s)",
+					budget.ID.Hex(), drift.StoredSpent, drift.ActualSpent)
+			}
+		}
+
+		report.BudgetsChecked += len(budgets)
+		afterID = budgets[len(budgets)-1].ID
+	}
+
+	report.DriftCount = len(report.Drifts)
+	report.FinishedAt = time.Now()
+
+	if err := s.reconciliationRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *reconciliationService) GetRecentReports(ctx context.Context, limit int) ([]*domain.ReconciliationReport, error) {
+	return s.reconciliationRepo.FindRecent(ctx, limit)
+}
+
+// repair recomputes one budget's spend and stores it inside a transaction so
+// expense writes racing with the repair are not lost.
+func (s *reconciliationService) repair(ctx context.Context, budgetID primitive.ObjectID) error {
+	var budget *domain.Budget
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		budget, err = s.budgetRepo.FindByID(ctx, budgetID)
+		if err != nil {
+			return err
+		}
+
+		spend, err := s.expenseRepo.SumSpendByBudget(ctx, []primitive.ObjectID{budgetID})
+		if err != nil {
+			return err
+		}
+		actual := groupSpendByBudget(spend)[budgetID]
+
+		budget.SpentAmount = 0
+		for _, total := range actual {
+			budget.SpentAmount += total
+		}
+		for i := range budget.Categories {
+			budget.Categories[i].SpentAmount = actual[budget.Categories[i].CategoryID]
+		}
+
+		return s.budgetRepo.SetSpentAmounts(ctx, budget)
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return nil
+}
+
+func groupSpendByBudget(spend []domain.CategorySpend) map[primitive.ObjectID]map[primitive.ObjectID]domain.Money {
+	grouped := make(map[primitive.ObjectID]map[primitive.ObjectID]domain.Money)
+	for _, item := range spend {
+		if grouped[item.BudgetID] == nil {
+			grouped[item.BudgetID] = make(map[primitive.ObjectID]domain.Money)
+		}
+		grouped[item.BudgetID][item.CategoryID] += item.Total
+	}
+	return grouped
+}
+
+// compareSpend returns the drift between the stored and recomputed spend of a
+// budget, or nil when they agree. Expenses in categories the budget no longer
+// tracks count towards the total and are reported with a stored amount of 0.
+func compareSpend(budget *domain.Budget, actual map[primitive.ObjectID]domain.Money) *domain.BudgetDrift {
+	drift := &domain.BudgetDrift{
+		BudgetID:    budget.ID,
+		UserID:      budget.UserID,
+		BudgetName:  budget.Name,
+		StoredSpent: budget.SpentAmount,
+		Categories:  []domain.CategoryDrift{},
+	}
+
+	for _, total := range actual {
+		drift.ActualSpent += total
+	}
+
+	drifted := drift.StoredSpent != drift.ActualSpent
+	tracked := make(map[primitive.ObjectID]bool, len(budget.Categories))
+	for _, category := range budget.Categories {
+		tracked[category.CategoryID] = true
+		if category.SpentAmount != actual[category.CategoryID] {
+			drifted = true
+			drift.Categories = append(drift.Categories, domain.CategoryDrift{
+				CategoryID: category.CategoryID,
+				Name:       category.Name,
+				Stored:     category.SpentAmount,
+				Actual:     actual[category.CategoryID],
+			})
+		}
+	}
+	for categoryID, total := range actual {
+		if !tracked[categoryID] {
+			drifted = true
+			drift.Categories = append(drift.Categories, domain.CategoryDrift{
+				CategoryID: categoryID,
+				Actual:     total,
+			})
+		}
+	}
+
+	if !drifted {
+		return nil
+	}
+	return drift
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecurringExpenseService interface {
+	CreateRecurringExpense(ctx context.Context, userID primitive.ObjectID, req *domain.CreateRecurringExpenseRequest) (*domain.RecurringExpense, error)
+	GetRecurringExpense(ctx context.Context, userID, recurringID primitive.ObjectID) (*domain.RecurringExpense, error)
+	GetUserRecurringExpenses(ctx context.Context, userID primitive.ObjectID) ([]*domain.RecurringExpense, error)
+	UpdateRecurringExpense(ctx context.Context, userID, recurringID primitive.ObjectID, req *domain.UpdateRecurringExpenseRequest) (*domain.RecurringExpense, error)
+	DeleteRecurringExpense(ctx context.Context, userID, recurringID primitive.ObjectID) error
+	GenerateDueExpenses(ctx context.Context, asOf time.Time) (int, error)
+}
+
+type recurringExpenseService struct {
+	recurringRepo   repository.RecurringExpenseRepository
+	budgetRepo      repository.BudgetRepository
+	expenseService  ExpenseService
+	categoryService CategoryService
+}
+
+func NewRecurringExpenseService(
+	recurringRepo repository.RecurringExpenseRepository,
+	budgetRepo repository.BudgetRepository,
+	expenseService ExpenseService,
+	categoryService CategoryService,
+) RecurringExpenseService {
+	return &recurringExpenseService{
+		recurringRepo:   recurringRepo,
+		budgetRepo:      budgetRepo,
+		expenseService:  expenseService,
+		categoryService: categoryService,
+	}
+}
+
+func (s *recurringExpenseService) CreateRecurringExpense(ctx context.Context, userID primitive.ObjectID, req *domain.CreateRecurringExpenseRequest) (*domain.RecurringExpense, error) {
+	if req.Rule.Until != nil && req.Rule.Until.Before(req.StartDate) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	recurring := &domain.RecurringExpense{
+		UserID:         userID,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		Description:    req.Description,
+		Rule:           req.Rule,
+		StartDate:      req.StartDate,
+		NextOccurrence: req.Rule.Occurrence(req.StartDate, 0),
+		IsActive:       true,
+	}
+
+	if err := s.setCategory(ctx, recurring, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.recurringRepo.Create(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+func (s *recurringExpenseService) GetRecurringExpense(ctx context.Context, userID, recurringID primitive.ObjectID) (*domain.RecurringExpense, error) {
+	recurring, err := s.recurringRepo.FindByID(ctx, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	if recurring.UserID != userID {
+		return nil, domain.ErrUnauthorized
+	}
+
+	return recurring, nil
+}
+
+func (s *recurringExpenseService) GetUserRecurringExpenses(ctx context.Context, userID primitive.ObjectID) ([]*domain.RecurringExpense, error) {
+	return s.recurringRepo.FindByUserID(ctx, userID)
+}
+
+func (s *recurringExpenseService) UpdateRecurringExpense(ctx context.Context, userID, recurringID primitive.ObjectID, req *domain.UpdateRecurringExpenseRequest) (*domain.RecurringExpense, error) {
+	recurring, err := s.GetRecurringExpense(ctx, userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Rule.Until != nil && req.Rule.Until.Before(req.StartDate) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	scheduleChanged := !req.StartDate.Equal(recurring.StartDate) ||
+		req.Rule.Frequency != recurring.Rule.Frequency ||
+		req.Rule.Interval != recurring.Rule.Interval
+
+	if err := s.setCategory(ctx, recurring, &req.CreateRecurringExpenseRequest); err != nil {
+		return nil, err
+	}
+	recurring.Amount = req.Amount
+	recurring.Currency = req.Currency
+	recurring.Description = req.Description
+	recurring.Rule = req.Rule
+	recurring.StartDate = req.StartDate
+	if req.IsActive != nil {
+		recurring.IsActive = *req.IsActive
+	}
+
+	if scheduleChanged {
+		// Restart the schedule but never backfill occurrences that fall
+		// before now; those belonged to the previous schedule.
+		recurring.OccurrenceCount = 0
+		now := time.Now()
+		for {
+			next := recurring.Rule.Occurrence(recurring.StartDate, recurring.OccurrenceCount)
+			if next == nil || !next.Before(now) {
+				recurring.NextOccurrence = next
+				break
+			}
+			recurring.OccurrenceCount++
+		}
+	} else {
+		recurring.NextOccurrence = recurring.Rule.Occurrence(recurring.StartDate, recurring.OccurrenceCount)
+	}
+
+	if err := s.recurringRepo.Update(ctx, recurring); err != nil {
+		return nil, err
+	}
+
+	return recurring, nil
+}
+
+func (s *recurringExpenseService) DeleteRecurringExpense(ctx context.Context, userID, recurringID primitive.ObjectID) error {
+	if _, err := s.GetRecurringExpense(ctx, userID, recurringID); err != nil {
+		return err
+	}
+
+	return s.recurringRepo.Delete(ctx, recurringID)
+}
+
+// setCategory points the recurring expense at the catalog category named in
+// the request. Keeping the current category is allowed even once it has been
+// archived.
+func (s *recurringExpenseService) setCategory(ctx context.Context, recurring *domain.RecurringExpense, req *domain.CreateRecurringExpenseRequest) error {
+	if req.CategoryID != "" && req.CategoryID == recurring.CategoryID.Hex() {
+		return nil
+	}
+
+	var categoryID primitive.ObjectID
+	if req.CategoryID != "" {
+		id, err := primitive.ObjectIDFromHex(req.CategoryID)
+		if err != nil {
+			return domain.ErrInvalidInput
+		}
+		categoryID = id
+	}
+
+	category, err := s.categoryService.ResolveCategory(ctx, recurring.UserID, categoryID, req.Category)
+	if err != nil {
+		return err
+	}
+
+	recurring.CategoryID = category.ID
+	recurring.Category = category.Name
+	return nil
+}
+
+// GenerateDueExpenses materializes every occurrence up to asOf as a concrete
+// expense in the user's active budget covering that date. Occurrences are
+// keyed by recurring expense and date, so re-running after a partial failure
+// never records the same occurrence twice. An occurrence no budget covers yet
+// stays due, along with the ones after it, until such a budget exists.
+func (s *recurringExpenseService) GenerateDueExpenses(ctx context.Context, asOf time.Time) (int, error) {
+	due, err := s.recurringRepo.FindDue(ctx, asOf)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, recurring := range due {
+		count := recurring.OccurrenceCount
+		next := recurring.NextOccurrence
+
+		for next != nil && !next.After(asOf) {
+			created, err := s.recordOccurrence(ctx, recurring, *next)
+			if err == domain.ErrBudgetNotFound {
+				// Keep the occurrence due so it is charged once a budget
+				// covering its date exists, instead of skipping it.
+				log.Printf("Recurring expense %s: no active budget for %s on %s, keeping the occurrence pending",
+					recurring.ID.Hex(), recurring.Category, next.Format(time.DateOnly))
+				break
+			}
+			if err != nil {
+				log.Printf("Recurring expense %s: failed to record occurrence %s: %v",
+					recurring.ID.Hex(), next.Format(time.DateOnly), err)
+				break
+			}
+			if created {
+				generated++
+			}
+
+			count++
+			next = recurring.Rule.Occurrence(recurring.StartDate, count)
+		}
+
+		if count == recurring.OccurrenceCount {
+			continue
+		}
+
+		if err := s.recurringRepo.UpdateSchedule(ctx, recurring.ID, count, next); err != nil {
+			log.Printf("Recurring expense %s: failed to update schedule: %v", recurring.ID.Hex(), err)
+		}
+	}
+
+	return generated, nil
+}
+
+func (s *recurringExpenseService) recordOccurrence(ctx context.Context, recurring *domain.RecurringExpense, date time.Time) (bool, error) {
+	budget, err := s.budgetRepo.FindActiveForDate(ctx, recurring.UserID, date, recurring.CategoryID)
+	if err != nil {
+		return false, err
+	}
+
+	recurringID := recurring.ID
+	occurrence := date
+	expense := &domain.Expense{
+		UserID:             budget.UserID,
+		CreatedBy:          recurring.UserID,
+		BudgetID:           budget.ID,
+		CategoryID:         recurring.CategoryID,
+		Category:           recurring.Category,
+		Currency:           budget.Currency,
+		OriginalAmount:     recurring.Amount,
+		OriginalCurrency:   recurring.Currency,
+		Description:        recurring.Description,
+		Date:               date,
+		RecurringExpenseID: &recurringID,
+		OccurrenceDate:     &occurrence,
+	}
+
+	if err := s.expenseService.RecordOccurrence(ctx, budget, expense); err != nil {
+		if err == domain.ErrOccurrenceExists {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const rolloverBatchSize = 100
+
+type RolloverService interface {
+	RolloverEndedBudgets(ctx context.Context, asOf time.Time) (int, error)
+}
+
+type rolloverService struct {
+	uow          repository.UnitOfWork
+	budgetRepo   repository.BudgetRepository
+	templateRepo repository.BudgetTemplateRepository
+	alertRepo    repository.AlertRepository
+	cache        cache.CacheService
+}
+
+func NewRolloverService(
+	uow repository.UnitOfWork,
+	budgetRepo repository.BudgetRepository,
+	templateRepo repository.BudgetTemplateRepository,
+	alertRepo repository.AlertRepository,
+	cache cache.CacheService,
+) RolloverService {
+	return &rolloverService{
+		uow:          uow,
+		budgetRepo:   budgetRepo,
+		templateRepo: templateRepo,
+		alertRepo:    alertRepo,
+		cache:        cache,
+	}
+}
+
+// RolloverEndedBudgets closes every active budget whose period ended by asOf
+// and opens its next period. A successor that has itself already ended, for
+// example after the worker was down, is rolled over in the same run. Budgets
+// that fail are skipped for the rest of the run, so they cannot hold up the
+// ones after them, and retried by the next run. It returns the number of
+// budgets closed.
+func (s *rolloverService) RolloverEndedBudgets(ctx context.Context, asOf time.Time) (int, error) {
+	closed := 0
+	var failed []primitive.ObjectID
+	for {
+		budgets, err := s.budgetRepo.FindEnded(ctx, asOf, failed, rolloverBatchSize)
+		if err != nil {
+			return closed, err
+		}
+		if len(budgets) == 0 {
+			return closed, nil
+		}
+
+		for _, budget := range budgets {
+			if err := s.rollover(ctx, budget); err != nil {
+				if err != domain.ErrBudgetRolledOver {
+					log.Printf("Rollover: budget %s failed: %v", budget.ID.Hex(), err)
+				}
+				failed = append(failed, budget.ID)
+				continue
+			}
+			closed++
+		}
+	}
+}
+
+// rollover creates the successor, moves the budget's alerts to it and closes
+// the budget in one transaction.
+// The unique index on previous_budget_id and the is_active guard on Close
+// both make a second run return domain.ErrBudgetRolledOver instead of
+// creating another successor. Custom-period budgets are one-off ranges and
+// are closed without a successor.
+func (s *rolloverService) rollover(ctx context.Context, budget *domain.Budget) error {
+	template, err := s.findTemplate(ctx, budget)
+	if err != nil {
+		return err
+	}
+
+	var next *domain.Budget
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if budget.Period != domain.BudgetPeriodCustom {
+			next = nextPeriodBudget(budget, template)
+			if err := s.budgetRepo.Create(ctx, next); err != nil {
+				return err
+			}
+			if err := s.alertRepo.MoveToBudget(ctx, budget.ID, next.ID); err != nil {
+				return err
+			}
+		}
+
+		var nextID *primitive.ObjectID
+		if next != nil {
+			nextID = &next.ID
+		}
+
+		closed, err := s.budgetRepo.Close(ctx, budget.ID, nextID)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return domain.ErrBudgetRolledOver
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	invalidateBudgetCache(ctx, s.cache, budget)
+
+	return nil
+}
+
+// findTemplate returns the template a budget was created from, or nil when
+// it has none or the template has since been deleted.
+func (s *rolloverService) findTemplate(ctx context.Context, budget *domain.Budget) (*domain.BudgetTemplate, error) {
+	if budget.TemplateID == nil {
+		return nil, nil
+	}
+
+	template, err := s.templateRepo.FindByID(ctx, *budget.TemplateID)
+	if err != nil {
+		if err == domain.ErrTemplateNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+// nextPeriodBudget builds the budget for the period after budget with no
+// spending. Its period, categories and planned amounts come from template
+// when there is one, so template edits apply from the next period, and from
+// budget otherwise. A changed period starts a new series where budget ends.
+// With CarryOver set, each category's unspent amount is added to its planned
+// amount, or its overspend deducted down to a limit of zero. Overspend beyond
+// the planned amount is reported in DroppedOverspend rather than carried.
+//
+// Envelope budgets have no planned amounts: envelopes start empty unless
+// CarryOver brings their balances forward, and the carried balances plus any
+// unassigned income become the new period's income.
+func nextPeriodBudget(budget *domain.Budget, template *domain.BudgetTemplate) *domain.Budget {
+	period := budget.Period
+	start, end := budget.NextPeriod()
+	anchor, index := budget.PeriodPosition()
+	index++
+	if template != nil && template.Period != period {
+		if templateEnd, err := template.Period.EndDate(budget.EndDate, nil); err == nil {
+			period = template.Period
+			start, end = budget.EndDate, templateEnd
+			anchor, index = start, 0
+		}
+	}
+	previousID := budget.ID
+
+	planned := make([]domain.BudgetCategory, len(budget.Categories))
+	for i, category := range budget.Categories {
+		planned[i] = domain.BudgetCategory{
+			CategoryID: category.CategoryID,
+			Name:       category.Name,
+			Amount:     category.Amount - category.CarriedOver,
+		}
+	}
+	if template != nil {
+		planned = template.BudgetCategories()
+	}
+
+	categories := make([]domain.BudgetCategory, len(planned))
+	total := domain.Money(0)
+	for i, category := range planned {
+		if budget.IsEnvelope() {
+			category.Amount = 0
+		}
+
+		amount := category.Amount
+		var dropped domain.Money
+		if previous := budget.FindCategory(category.CategoryID); previous != nil && budget.CarryOver {
+			amount += previous.Amount - previous.SpentAmount
+			if amount < 0 {
+				dropped = -amount
+				amount = 0
+			}
+		}
+
+		categories[i] = domain.BudgetCategory{
+			CategoryID:       category.CategoryID,
+			Name:             category.Name,
+			Amount:           amount,
+			CarriedOver:      amount - category.Amount,
+			DroppedOverspend: dropped,
+		}
+		total += amount
+	}
+
+	var income domain.Money
+	if budget.IsEnvelope() {
+		income = total
+		if budget.CarryOver && budget.ToBeAssigned() > 0 {
+			income += budget.ToBeAssigned()
+		}
+	}
+
+	return &domain.Budget{
+		UserID:           budget.UserID,
+		Name:             budget.Name,
+		Period:           period,
+		Mode:             budget.Mode,
+		Currency:         budget.Currency,
+		CarryOver:        budget.CarryOver,
+		StartDate:        start,
+		EndDate:          end,
+		PeriodAnchor:     anchor,
+		PeriodIndex:      index,
+		Categories:       categories,
+		TotalAmount:      total,
+		IncomeAmount:     income,
+		PreviousBudgetID: &previousID,
+		TemplateID:       budget.TemplateID,
+		Members:          budget.Members,
+	}
+}
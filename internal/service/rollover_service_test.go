@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNextPeriodBudgetCarryOver(t *testing.T) {
+	food := primitive.NewObjectID()
+	rent := primitive.NewObjectID()
+	travel := primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		mode       domain.BudgetMode
+		carryOver  bool
+		categories []domain.BudgetCategory
+		income     domain.Money
+		template   *domain.BudgetTemplate
+		want       []domain.BudgetCategory
+		wantTotal  domain.Money
+		wantIncome domain.Money
+	}{
+		{
+			name: "without carry over the plan repeats",
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 20000},
+			},
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000},
+			},
+			wantTotal: 50000,
+		},
+		{
+			name:      "unspent amount is carried",
+			carryOver: true,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 30000},
+			},
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 70000, CarriedOver: 20000},
+			},
+			wantTotal: 70000,
+		},
+		{
+			name:      "overspend is deducted",
+			carryOver: true,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 60000},
+			},
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 40000, CarriedOver: -10000},
+			},
+			wantTotal: 40000,
+		},
+		{
+			name:      "overspend beyond the plan is dropped and reported",
+			carryOver: true,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 120000},
+			},
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 0, CarriedOver: -50000, DroppedOverspend: 20000},
+			},
+			wantTotal: 0,
+		},
+		{
+			name:      "previous carry over is not planned again",
+			carryOver: true,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 70000, CarriedOver: 20000, SpentAmount: 10000},
+				{CategoryID: rent, Name: "Rent", Amount: 100000, SpentAmount: 100000},
+			},
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 110000, CarriedOver: 60000},
+				{CategoryID: rent, Name: "Rent", Amount: 100000},
+			},
+			wantTotal: 210000,
+		},
+		{
+			name:      "template categories replace the budget's",
+			carryOver: true,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 40000},
+				{CategoryID: rent, Name: "Rent", Amount: 100000},
+			},
+			template: &domain.BudgetTemplate{
+				Period: domain.BudgetPeriodMonthly,
+				Categories: []domain.TemplateCategory{
+					{CategoryID: food, Name: "Groceries", Amount: 60000},
+					{CategoryID: travel, Name: "Travel", Amount: 25000},
+				},
+			},
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Groceries", Amount: 70000, CarriedOver: 10000},
+				{CategoryID: travel, Name: "Travel", Amount: 25000},
+			},
+			wantTotal: 95000,
+		},
+		{
+			name: "envelopes start empty",
+			mode: domain.BudgetModeEnvelope,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 20000},
+			},
+			income: 80000,
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 0},
+			},
+		},
+		{
+			name:      "envelope balances and unassigned income are carried",
+			mode:      domain.BudgetModeEnvelope,
+			carryOver: true,
+			categories: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 50000, SpentAmount: 20000},
+				{CategoryID: rent, Name: "Rent", Amount: 10000, SpentAmount: 15000},
+			},
+			income: 80000,
+			want: []domain.BudgetCategory{
+				{CategoryID: food, Name: "Food", Amount: 30000, CarriedOver: 30000},
+				{CategoryID: rent, Name: "Rent", Amount: 0, CarriedOver: 0, DroppedOverspend: 5000},
+			},
+			wantTotal:  30000,
+			wantIncome: 50000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &domain.Budget{
+				ID:         primitive.NewObjectID(),
+				Period:     domain.BudgetPeriodMonthly,
+				Mode:       tt.mode,
+				CarryOver:  tt.carryOver,
+				StartDate:  time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:    time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+				Categories: tt.categories,
+			}
+			for _, category := range tt.categories {
+				budget.TotalAmount += category.Amount
+			}
+			budget.IncomeAmount = tt.income
+
+			next := nextPeriodBudget(budget, tt.template)
+
+			if !reflect.DeepEqual(next.Categories, tt.want) {
+				t.Errorf("categories = %+v, want %+v", next.Categories, tt.want)
+			}
+			if next.TotalAmount != tt.wantTotal {
+				t.Errorf("total = %d, want %d", next.TotalAmount, tt.wantTotal)
+			}
+			if next.IncomeAmount != tt.wantIncome {
+				t.Errorf("income = %d, want %d", next.IncomeAmount, tt.wantIncome)
+			}
+			if next.PreviousBudgetID == nil || *next.PreviousBudgetID != budget.ID {
+				t.Errorf("previous budget = %v, want %s", next.PreviousBudgetID, budget.ID.Hex())
+			}
+		})
+	}
+}
+
+func TestNextPeriodBudgetPeriod(t *testing.T) {
+	anchor := time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		template   *domain.BudgetTemplate
+		wantPeriod domain.BudgetPeriod
+		wantStart  time.Time
+		wantEnd    time.Time
+		wantAnchor time.Time
+		wantIndex  int
+	}{
+		{
+			name:       "series continues from its anchor",
+			wantPeriod: domain.BudgetPeriodMonthly,
+			wantStart:  time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+			wantEnd:    time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+			wantAnchor: anchor,
+			wantIndex:  2,
+		},
+		{
+			name:       "template with the same period continues the series",
+			template:   &domain.BudgetTemplate{Period: domain.BudgetPeriodMonthly},
+			wantPeriod: domain.BudgetPeriodMonthly,
+			wantStart:  time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+			wantEnd:    time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC),
+			wantAnchor: anchor,
+			wantIndex:  2,
+		},
+		{
+			name:       "changed template period starts a new series",
+			template:   &domain.BudgetTemplate{Period: domain.BudgetPeriodWeekly},
+			wantPeriod: domain.BudgetPeriodWeekly,
+			wantStart:  time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+			wantEnd:    time.Date(2025, time.April, 7, 0, 0, 0, 0, time.UTC),
+			wantAnchor: time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+			wantIndex:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &domain.Budget{
+				ID:           primitive.NewObjectID(),
+				Period:       domain.BudgetPeriodMonthly,
+				StartDate:    time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC),
+				EndDate:      time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+				PeriodAnchor: anchor,
+				PeriodIndex:  1,
+			}
+
+			next := nextPeriodBudget(budget, tt.template)
+
+			if next.Period != tt.wantPeriod {
+				t.Errorf("period = %s, want %s", next.Period, tt.wantPeriod)
+			}
+			if !next.StartDate.Equal(tt.wantStart) || !next.EndDate.Equal(tt.wantEnd) {
+				t.Errorf("dates = %s - %s, want %s - %s", next.StartDate, next.EndDate, tt.wantStart, tt.wantEnd)
+			}
+			if !next.PeriodAnchor.Equal(tt.wantAnchor) || next.PeriodIndex != tt.wantIndex {
+				t.Errorf("position = %s #%d, want %s #%d", next.PeriodAnchor, next.PeriodIndex, tt.wantAnchor, tt.wantIndex)
+			}
+		})
+	}
+}
+
+// rolloverBudgets is an in-memory BudgetRepository holding just enough
+// behaviour for RolloverEndedBudgets. Methods it does not override panic
+// through the nil embedded interface.
+type rolloverBudgets struct {
+	repository.BudgetRepository
+	budgets   []*domain.Budget
+	createErr map[primitive.ObjectID]error
+}
+
+func (r *rolloverBudgets) FindEnded(ctx context.Context, asOf time.Time, exclude []primitive.ObjectID, limit int) ([]*domain.Budget, error) {
+	var ended []*domain.Budget
+	for _, budget := range r.budgets {
+		if budget.IsActive && !budget.EndDate.After(asOf) && !slices.Contains(exclude, budget.ID) {
+			ended = append(ended, budget)
+		}
+	}
+	slices.SortStableFunc(ended, func(a, b *domain.Budget) int {
+		return a.EndDate.Compare(b.EndDate)
+	})
+	if len(ended) > limit {
+		ended = ended[:limit]
+	}
+	return ended, nil
+}
+
+func (r *rolloverBudgets) Create(ctx context.Context, budget *domain.Budget) error {
+	if err := r.createErr[*budget.PreviousBudgetID]; err != nil {
+		return err
+	}
+	for _, existing := range r.budgets {
+		if existing.PreviousBudgetID != nil && *existing.PreviousBudgetID == *budget.PreviousBudgetID {
+			return domain.ErrBudgetRolledOver
+		}
+	}
+	budget.ID = primitive.NewObjectID()
+	budget.IsActive = true
+	r.budgets = append(r.budgets, budget)
+	return nil
+}
+
+func (r *rolloverBudgets) Close(ctx context.Context, id primitive.ObjectID, nextID *primitive.ObjectID) (bool, error) {
+	for _, budget := range r.budgets {
+		if budget.ID == id && budget.IsActive {
+			budget.IsActive = false
+			budget.NextBudgetID = nextID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type rolloverAlerts struct {
+	repository.AlertRepository
+}
+
+func (rolloverAlerts) MoveToBudget(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	return nil
+}
+
+type directUnitOfWork struct{}
+
+func (directUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// noopCache always misses and stores nothing.
+type noopCache struct{}
+
+func (noopCache) Get(ctx context.Context, key string, dest any) error {
+	return errors.New("cache miss")
+}
+
+func (noopCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return nil
+}
+
+func (noopCache) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (noopCache) DeletePattern(ctx context.Context, pattern string) error {
+	return nil
+}
+
+func TestRolloverEndedBudgets(t *testing.T) {
+	asOf := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+	monthly := func(start time.Time) *domain.Budget {
+		return &domain.Budget{
+			ID:        primitive.NewObjectID(),
+			Period:    domain.BudgetPeriodMonthly,
+			StartDate: start,
+			EndDate:   start.AddDate(0, 1, 0),
+			IsActive:  true,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(r *rolloverBudgets)
+		wantClosed int
+		wantActive int
+	}{
+		{
+			name: "catches up on every missed period",
+			setup: func(r *rolloverBudgets) {
+				r.budgets = append(r.budgets, monthly(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)))
+			},
+			wantClosed: 2,
+			wantActive: 1,
+		},
+		{
+			name: "budget with a successor is not rolled over again",
+			setup: func(r *rolloverBudgets) {
+				budget := monthly(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC))
+				successor := monthly(budget.EndDate)
+				successor.PreviousBudgetID = &budget.ID
+				r.budgets = append(r.budgets, budget, successor)
+			},
+			wantClosed: 0,
+			wantActive: 2,
+		},
+		{
+			name: "failed budget does not hold up the rest",
+			setup: func(r *rolloverBudgets) {
+				failing := monthly(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+				healthy := monthly(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC))
+				r.budgets = append(r.budgets, failing, healthy)
+				r.createErr = map[primitive.ObjectID]error{failing.ID: errors.New("insert failed")}
+			},
+			wantClosed: 1,
+			wantActive: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budgets := &rolloverBudgets{}
+			tt.setup(budgets)
+			svc := NewRolloverService(directUnitOfWork{}, budgets, nil, rolloverAlerts{}, noopCache{})
+
+			closed, err := svc.RolloverEndedBudgets(context.Background(), asOf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if closed != tt.wantClosed {
+				t.Errorf("closed = %d, want %d", closed, tt.wantClosed)
+			}
+
+			again, err := svc.RolloverEndedBudgets(context.Background(), asOf)
+			if err != nil {
+				t.Fatalf("second run: %v", err)
+			}
+			if again != 0 {
+				t.Errorf("second run closed %d, want 0", again)
+			}
+
+			active := 0
+			for _, budget := range budgets.budgets {
+				if budget.IsActive {
+					active++
+				}
+			}
+			if active != tt.wantActive {
+				t.Errorf("active budgets = %d, want %d", active, tt.wantActive)
+			}
+		})
+	}
+}
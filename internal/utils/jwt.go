@@ -8,39 +8,79 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	TokenType TokenType `json:"token_type"`
 	jwt.RegisteredClaims
 }
 
 type JWTAuth struct {
-	secretKey string
-	expiry    time.Duration
+	secretKey  string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewJWTAuth(secretKey string, expiry time.Duration) *JWTAuth {
+func NewJWTAuth(secretKey string, accessTTL, refreshTTL time.Duration) *JWTAuth {
 	return &JWTAuth{
-		secretKey: secretKey,
-		expiry:    expiry,
+		secretKey:  secretKey,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
 	}
 }
 
+// GenerateToken issues a short-lived access token.
 func (a *JWTAuth) GenerateToken(userID, email string) (string, error) {
+	token, _, err := a.sign(userID, email, TokenTypeAccess, "", a.accessTTL)
+	return token, err
+}
+
+// GenerateRefreshToken issues a refresh token identified by tokenID, which is
+// used as the JWT ID so the token can be looked up and revoked server-side.
+func (a *JWTAuth) GenerateRefreshToken(userID, email, tokenID string) (string, time.Time, error) {
+	return a.sign(userID, email, TokenTypeRefresh, tokenID, a.refreshTTL)
+}
+
+func (a *JWTAuth) ValidateToken(tokenString string) (*Claims, error) {
+	return a.validate(tokenString, TokenTypeAccess)
+}
+
+func (a *JWTAuth) ValidateRefreshToken(tokenString string) (*Claims, error) {
+	return a.validate(tokenString, TokenTypeRefresh)
+}
+
+func (a *JWTAuth) sign(userID, email string, tokenType TokenType, tokenID string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(a.expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(a.secretKey))
+	signed, err := token.SignedString([]byte(a.secretKey))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
 }
 
-func (a *JWTAuth) ValidateToken(tokenString string) (*Claims, error) {
+func (a *JWTAuth) validate(tokenString string, expectedType TokenType) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
@@ -49,10 +89,13 @@ func (a *JWTAuth) ValidateToken(tokenString string) (*Claims, error) {
 	})
 
 	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.ErrTokenExpired
+		}
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.TokenType == expectedType {
 		return claims, nil
 	}
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dmehra2102/budget-tracker/internal/domain"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -12,8 +13,13 @@ type Validator struct {
 }
 
 func NewValidator() *Validator {
+	validate := validator.New()
+	validate.RegisterValidation("budget_period", func(fl validator.FieldLevel) bool {
+		return domain.BudgetPeriod(fl.Field().String()).IsValid()
+	})
+
 	return &Validator{
-		validate: validator.New(),
+		validate: validate,
 	}
 }
 
@@ -49,8 +55,20 @@ func getErrorMessage(e validator.FieldError) string {
 		return fmt.Sprintf("must be greater than %s", e.Param())
 	case "gte":
 		return fmt.Sprintf("must be greater than or equal to %s", e.Param())
+	case "required_without":
+		return fmt.Sprintf("is required when %s is empty", e.Param())
+	case "nefield":
+		return fmt.Sprintf("must differ from %s", e.Param())
+	case "ne":
+		return fmt.Sprintf("must not be %s", e.Param())
 	case "oneof":
 		return fmt.Sprintf("must be one of: %s", e.Param())
+	case "unique":
+		return "must not contain duplicates"
+	case "budget_period":
+		return "must be one of: daily weekly biweekly monthly quarterly yearly custom"
+	case "iso4217":
+		return "must be a valid ISO 4217 currency code"
 	default:
 		return "is invalid"
 	}
@@ -5,7 +5,9 @@ import (
 	"log"
 	"time"
 
+	"github.com/dmehra2102/budget-tracker/internal/cache"
 	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
 	"github.com/dmehra2102/budget-tracker/internal/repository"
 	"github.com/dmehra2102/budget-tracker/internal/service"
 	"github.com/robfig/cron/v3"
@@ -13,26 +15,73 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// alertCheckLease keeps replicas from running the full alert check at the
+// same time, which would only repeat the work. It does not stop duplicate
+// notifications: the lease is not renewed and may lapse during a slow send,
+// so each tier is claimed atomically before it is sent instead.
+const alertCheckLease = "alert-check"
+
+// goalCheckLease keeps replicas from running the goal check at the same time.
+// Each reminder is also claimed before it is sent.
+const goalCheckLease = "goal-check"
+
+// reconcileLease keeps reconciliation runs from overlapping. A run that
+// repairs drift against totals another run is repairing would report the
+// same drift twice and count it as persistent.
+const reconcileLease = "reconcile"
+
+// rolloverLease keeps replicas from rolling over the same budgets at the
+// same time. Rollover is idempotent, so overlapping runs would only repeat
+// the work.
+const rolloverLease = "rollover"
+
+// recurringExpenseLease keeps replicas from generating recurring expenses at
+// the same time. Occurrences are keyed, so overlapping runs could not record
+// one twice, but they would race to advance the same schedules.
+const recurringExpenseLease = "recurring-expense"
+
+// cleanupLease keeps replicas from running the cleanup at the same time.
+const cleanupLease = "cleanup"
+
+// leaseMargin keeps a lease alive a little past the deadline of the work it
+// guards, so it cannot expire while that work is still winding down.
+const leaseMargin = 30 * time.Second
+
 type CronWorker struct {
-	cron         *cron.Cron
-	cfg          *config.Config
-	db           *mongo.Database
-	alertService service.AlertService
-	budgetRepo   repository.BudgetRepository
+	cron                  *cron.Cron
+	cfg                   *config.Config
+	db                    *mongo.Database
+	locker                cache.Locker
+	alertService          service.AlertService
+	recurringService      service.RecurringExpenseService
+	reconciliationService service.ReconciliationService
+	rolloverService       service.RolloverService
+	goalService           service.GoalService
+	budgetRepo            repository.BudgetRepository
 }
 
 func NewCronWorker(
 	cfg *config.Config,
 	db *mongo.Database,
+	locker cache.Locker,
 	alertService service.AlertService,
+	recurringService service.RecurringExpenseService,
+	reconciliationService service.ReconciliationService,
+	rolloverService service.RolloverService,
+	goalService service.GoalService,
 	budgetRepo repository.BudgetRepository,
 ) *CronWorker {
 	return &CronWorker{
-		cron:         cron.New(),
-		cfg:          cfg,
-		db:           db,
-		alertService: alertService,
-		budgetRepo:   budgetRepo,
+		cron:                  cron.New(),
+		cfg:                   cfg,
+		db:                    db,
+		locker:                locker,
+		alertService:          alertService,
+		recurringService:      recurringService,
+		reconciliationService: reconciliationService,
+		rolloverService:       rolloverService,
+		goalService:           goalService,
+		budgetRepo:            budgetRepo,
 	}
 }
 
@@ -47,6 +96,26 @@ func (w *CronWorker) Start() error {
 		return err
 	}
 
+	_, err = w.cron.AddFunc(w.cfg.Worker.RecurringExpenseSchedule, w.recurringExpenseJob)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.cron.AddFunc(w.cfg.Worker.ReconcileSchedule, w.reconcileJob)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.cron.AddFunc(w.cfg.Worker.RolloverSchedule, w.rolloverJob)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.cron.AddFunc(w.cfg.Worker.GoalCheckSchedule, w.goalCheckJob)
+	if err != nil {
+		return err
+	}
+
 	w.cron.Start()
 	log.Println("Cron worker started")
 	return nil
@@ -58,9 +127,18 @@ func (w *CronWorker) Stop() {
 }
 
 func (w *CronWorker) cleanupJob() {
-	ctx := context.Background()
 	log.Println("Running cleanup job...")
 
+	ran := withLease(w.locker, cleanupLease, w.cfg.Worker.JobTimeout, w.cleanup)
+	if !ran {
+		log.Println("Cleanup skipped, another worker is running it")
+		return
+	}
+
+	log.Println("Cleanup job completed")
+}
+
+func (w *CronWorker) cleanup(ctx context.Context) {
 	// Cleanup expired tokens
 	cutoffDate := time.Now().AddDate(0, 0, -w.cfg.Worker.ExpiredTokenDays)
 	_, err := w.db.Collection("users").UpdateMany(
@@ -74,24 +152,167 @@ func (w *CronWorker) cleanupJob() {
 	}
 
 	// Cleanup old alert notifications
+	notificationCutoff := time.Now().AddDate(0, 0, -w.cfg.Notification.RetentionDays)
 	_, err = w.db.Collection("alert_notifications").DeleteMany(
 		ctx,
-		bson.M{"sent_at": bson.M{"$lt": cutoffDate}},
+		bson.M{"sent_at": bson.M{"$lt": notificationCutoff}},
 	)
 	if err != nil {
 		log.Printf("Cleanup error: %v", err)
 	}
 
-	log.Println("Cleanup job completed")
+	// Cleanup processed and dead outbox events
+	outboxCutoff := time.Now().AddDate(0, 0, -w.cfg.Worker.OutboxRetentionDays)
+	_, err = w.db.Collection("outbox_events").DeleteMany(
+		ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"processed_at": bson.M{"$lt": outboxCutoff}},
+			bson.M{"dead_at": bson.M{"$lt": outboxCutoff}},
+		}},
+	)
+	if err != nil {
+		log.Printf("Cleanup error: %v", err)
+	}
+
+	dead, err := w.db.Collection("outbox_events").CountDocuments(ctx, bson.M{"dead_at": bson.M{"$ne": nil}})
+	if err != nil {
+		log.Printf("Cleanup error: %v", err)
+	} else if dead > 0 {
+		log.Printf("Outbox holds %d dead events, kept for %d days", dead, w.cfg.Worker.OutboxRetentionDays)
+	}
 }
 
+// alertCheckJob evaluates every alert. Alerts are normally evaluated as soon
+// as the outbox poller sees an expense change; this run catches anything it
+// missed, such as events that exhausted their attempts.
 func (w *CronWorker) alertCheckJob() {
-	ctx := context.Background()
 	log.Println("Running alert check job...")
 
-	if err := w.alertService.CheckAndSendAlerts(ctx); err != nil {
+	var err error
+	ran := withLease(w.locker, alertCheckLease, w.cfg.Worker.AlertCheckTimeout, func(ctx context.Context) {
+		err = w.alertService.CheckAndSendAlerts(ctx)
+	})
+	if !ran {
+		log.Println("Alert check skipped, another worker is running it")
+		return
+	}
+	if err != nil {
 		log.Printf("Alert check error: %v", err)
+		return
 	}
 
 	log.Println("Alert check completed")
 }
+
+func (w *CronWorker) recurringExpenseJob() {
+	log.Println("Running recurring expense job...")
+
+	var (
+		generated int
+		err       error
+	)
+	ran := withLease(w.locker, recurringExpenseLease, w.cfg.Worker.JobTimeout, func(ctx context.Context) {
+		generated, err = w.recurringService.GenerateDueExpenses(ctx, time.Now())
+	})
+	if !ran {
+		log.Println("Recurring expense job skipped, another worker is running it")
+		return
+	}
+	if err != nil {
+		log.Printf("Recurring expense error: %v", err)
+		return
+	}
+
+	log.Printf("Recurring expense job completed, %d expenses generated", generated)
+}
+
+func (w *CronWorker) reconcileJob() {
+	log.Println("Running reconciliation job...")
+
+	var (
+		report *domain.ReconciliationReport
+		err    error
+	)
+	ran := withLease(w.locker, reconcileLease, w.cfg.Worker.JobTimeout, func(ctx context.Context) {
+		report, err = w.reconciliationService.Reconcile(ctx, domain.ReconciliationTriggerWorker, w.cfg.Worker.ReconcileRepair)
+	})
+	if !ran {
+		log.Println("Reconciliation skipped, another worker is running it")
+		return
+	}
+	if err != nil {
+		log.Printf("Reconciliation error: %v", err)
+		return
+	}
+
+	log.Printf("Reconciliation completed: %d budgets checked, %d drifted, %d persistently",
+		report.BudgetsChecked, report.DriftCount, report.PersistentCount)
+}
+
+func (w *CronWorker) rolloverJob() {
+	log.Println("Running budget rollover job...")
+
+	var (
+		closed int
+		err    error
+	)
+	ran := withLease(w.locker, rolloverLease, w.cfg.Worker.JobTimeout, func(ctx context.Context) {
+		closed, err = w.rolloverService.RolloverEndedBudgets(ctx, time.Now())
+	})
+	if !ran {
+		log.Println("Budget rollover skipped, another worker is running it")
+		return
+	}
+	if err != nil {
+		log.Printf("Budget rollover error: %v", err)
+		return
+	}
+
+	log.Printf("Budget rollover completed, %d budgets rolled over", closed)
+}
+
+func (w *CronWorker) goalCheckJob() {
+	log.Println("Running goal check job...")
+
+	var (
+		sent int
+		err  error
+	)
+	ran := withLease(w.locker, goalCheckLease, w.cfg.Worker.JobTimeout, func(ctx context.Context) {
+		sent, err = w.goalService.NotifyBehindSchedule(ctx, time.Now())
+	})
+	if !ran {
+		log.Println("Goal check skipped, another worker is running it")
+		return
+	}
+	if err != nil {
+		log.Printf("Goal check error: %v", err)
+		return
+	}
+
+	log.Printf("Goal check completed, %d reminders sent", sent)
+}
+
+// withLease runs fn with a deadline of timeout while holding the named lease.
+// It reports false, without running fn, when the lease could not be taken.
+func withLease(locker cache.Locker, key string, timeout time.Duration, fn func(ctx context.Context)) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	lease, err := locker.Acquire(ctx, key, timeout+leaseMargin)
+	if err != nil {
+		if err != cache.ErrLeaseHeld {
+			log.Printf("Lease %s error: %v", key, err)
+		}
+		return false
+	}
+	defer func() {
+		// Release even if fn ran out the deadline.
+		if err := lease.Release(context.Background()); err != nil {
+			log.Printf("Lease %s release error: %v", key, err)
+		}
+	}()
+
+	fn(ctx)
+	return true
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dmehra2102/budget-tracker/internal/cache"
+	"github.com/dmehra2102/budget-tracker/internal/config"
+	"github.com/dmehra2102/budget-tracker/internal/domain"
+	"github.com/dmehra2102/budget-tracker/internal/repository"
+	"github.com/dmehra2102/budget-tracker/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// outboxLease is how long a claimed event stays locked before another
+	// poller may claim it again.
+	outboxLease = time.Minute
+	// outboxPollLease keeps replicas from polling at the same time. Events
+	// are claimed one by one, so overlapping polls would be safe, just
+	// wasteful.
+	outboxPollLease = "outbox-poll"
+	// outboxBatchSize bounds the events claimed in one poll.
+	outboxBatchSize = 100
+)
+
+// OutboxPoller consumes the expense events in the outbox and evaluates the
+// alerts of each affected budget, so alerts fire within seconds of the
+// spending that crossed them.
+type OutboxPoller struct {
+	cfg          *config.Config
+	locker       cache.Locker
+	outboxRepo   repository.OutboxRepository
+	alertService service.AlertService
+	stop         chan struct{}
+	done         sync.WaitGroup
+}
+
+func NewOutboxPoller(
+	cfg *config.Config,
+	locker cache.Locker,
+	outboxRepo repository.OutboxRepository,
+	alertService service.AlertService,
+) *OutboxPoller {
+	return &OutboxPoller{
+		cfg:          cfg,
+		locker:       locker,
+		outboxRepo:   outboxRepo,
+		alertService: alertService,
+		stop:         make(chan struct{}),
+	}
+}
+
+func (p *OutboxPoller) Start() {
+	p.done.Add(1)
+	go func() {
+		defer p.done.Done()
+
+		ticker := time.NewTicker(p.cfg.Worker.OutboxPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				withLease(p.locker, outboxPollLease, outboxLease, p.poll)
+			}
+		}
+	}()
+	log.Println("Outbox poller started")
+}
+
+// Stop waits for the poll in progress to finish.
+func (p *OutboxPoller) Stop() {
+	close(p.stop)
+	p.done.Wait()
+	log.Println("Outbox poller stopped")
+}
+
+// poll claims the pending events and evaluates each affected budget once,
+// however many of its expenses changed.
+func (p *OutboxPoller) poll(ctx context.Context) {
+	maxAttempts := p.cfg.Worker.OutboxMaxAttempts
+	if exhausted, err := p.outboxRepo.MarkExhausted(ctx, maxAttempts); err != nil {
+		log.Printf("Outbox error: %v", err)
+	} else if exhausted > 0 {
+		log.Printf("Outbox: %d events marked dead after their last attempt timed out", exhausted)
+	}
+
+	events := make(map[primitive.ObjectID][]*domain.OutboxEvent)
+	var budgets []primitive.ObjectID
+	for i := 0; i < outboxBatchSize; i++ {
+		event, err := p.outboxRepo.Claim(ctx, outboxLease, maxAttempts)
+		if err != nil {
+			log.Printf("Outbox claim error: %v", err)
+			break
+		}
+		if event == nil {
+			break
+		}
+
+		if _, ok := events[event.BudgetID]; !ok {
+			budgets = append(budgets, event.BudgetID)
+		}
+		events[event.BudgetID] = append(events[event.BudgetID], event)
+	}
+
+	for _, budgetID := range budgets {
+		checkErr := p.alertService.CheckBudgetAlerts(ctx, budgetID)
+		if checkErr != nil {
+			log.Printf("Alert check error for budget %s: %v", budgetID.Hex(), checkErr)
+		}
+
+		for _, event := range events[budgetID] {
+			var err error
+			if checkErr != nil {
+				dead := event.Attempts >= maxAttempts
+				if dead {
+					log.Printf("Outbox event %s is dead after %d attempts", event.ID.Hex(), event.Attempts)
+				}
+				err = p.outboxRepo.MarkFailed(ctx, event.ID, checkErr, dead)
+			} else {
+				err = p.outboxRepo.MarkProcessed(ctx, event.ID)
+			}
+			if err != nil {
+				log.Printf("Outbox update error: %v", err)
+			}
+		}
+	}
+}
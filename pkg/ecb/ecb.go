@@ -0,0 +1,60 @@
+// Package ecb parses the European Central Bank euro foreign exchange
+// reference rate files (eurofxref-daily.xml, eurofxref-hist.xml and
+// eurofxref-hist-90d.xml).
+package ecb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// BaseCurrency is the currency every ECB reference rate is quoted against.
+const BaseCurrency = "EUR"
+
+// Rate is the amount of Currency one euro buys. Value is kept as the decimal
+// text published by the ECB so no precision is lost.
+type Rate struct {
+	Currency string
+	Value    string
+}
+
+type DailyRates struct {
+	Date  time.Time
+	Rates []Rate
+}
+
+type envelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// Parse reads an ECB reference rate file. Dates are returned as midnight UTC.
+func Parse(r io.Reader) ([]DailyRates, error) {
+	var doc envelope
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	days := make([]DailyRates, 0, len(doc.Days))
+	for _, day := range doc.Days {
+		date, err := time.Parse(time.DateOnly, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("ecb: invalid date %q: %w", day.Time, err)
+		}
+
+		rates := make([]Rate, 0, len(day.Rates))
+		for _, rate := range day.Rates {
+			rates = append(rates, Rate{Currency: rate.Currency, Value: rate.Rate})
+		}
+		days = append(days, DailyRates{Date: date, Rates: rates})
+	}
+
+	return days, nil
+}
@@ -0,0 +1,418 @@
+// Package patch applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902)
+// documents to JSON values.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported patch media type")
+	ErrInvalidPatch         = errors.New("invalid patch document")
+	ErrPathNotFound         = errors.New("patch path not found")
+	ErrTestFailed           = errors.New("patch test operation failed")
+)
+
+// Apply applies patch to doc using the format named by the request's
+// Content-Type.
+func Apply(contentType string, doc, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case MediaTypeMergePatch:
+		return MergePatch(doc, patch)
+	case MediaTypeJSONPatch:
+		return JSONPatch(doc, patch)
+	default:
+		return nil, ErrUnsupportedMediaType
+	}
+}
+
+// MergePatch applies an RFC 7386 merge patch. Members set to null are
+// removed and arrays are replaced as a whole.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := decode(patch)
+	if err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergeValue(targetObject[name], value)
+	}
+	return targetObject
+}
+
+type operation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies an RFC 6902 patch. The operations are applied in order
+// and the patch fails as a whole if any of them fails.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	for _, op := range ops {
+		if op.Path == nil {
+			return nil, ErrInvalidPatch
+		}
+		path, err := parsePointer(*op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, ErrInvalidPatch
+			}
+			value, err := decode(*op.Value)
+			if err != nil {
+				return nil, ErrInvalidPatch
+			}
+			switch op.Op {
+			case "add":
+				target, err = add(target, path, value)
+			case "replace":
+				target, err = replace(target, path, value)
+			case "test":
+				var current any
+				if current, err = get(target, path); err == nil && !equal(current, value) {
+					err = ErrTestFailed
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		case "remove":
+			if target, err = remove(target, path); err != nil {
+				return nil, err
+			}
+		case "move", "copy":
+			if op.From == nil {
+				return nil, ErrInvalidPatch
+			}
+			from, err := parsePointer(*op.From)
+			if err != nil {
+				return nil, err
+			}
+			value, err := get(target, from)
+			if err != nil {
+				return nil, err
+			}
+			if op.Op == "move" {
+				if isPrefix(from, path) && len(from) < len(path) {
+					return nil, ErrInvalidPatch
+				}
+				if target, err = remove(target, from); err != nil {
+					return nil, err
+				}
+			} else {
+				value = clone(value)
+			}
+			if target, err = add(target, path, value); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, ErrInvalidPatch
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// decode parses a JSON value keeping numbers as json.Number, so large integers
+// survive the round trip unchanged.
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, ErrInvalidPatch
+	}
+	return value, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference
+// tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, ErrInvalidPatch
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			doc = value
+		case []any:
+			i, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return doc, nil
+}
+
+// add sets the value at path, inserting into arrays, and returns the new
+// document.
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return doc, nil
+	case []any:
+		i := len(node)
+		if last != "-" {
+			if i, err = arrayIndex(last, len(node)); err != nil {
+				return nil, err
+			}
+		}
+		node = append(node, nil)
+		copy(node[i+1:], node[i:])
+		node[i] = value
+		return set(doc, path[:len(path)-1], node)
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// replace swaps the existing value at path, which may be the whole document,
+// for value and returns the new document.
+func replace(doc any, path []string, value any) (any, error) {
+	if _, err := get(doc, path); err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	doc, err := remove(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	return add(doc, path, value)
+}
+
+// remove deletes the value at path and returns the new document.
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, ErrInvalidPatch
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		if _, ok := node[last]; !ok {
+			return nil, ErrPathNotFound
+		}
+		delete(node, last)
+		return doc, nil
+	case []any:
+		i, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node[:i:i], node[i+1:]...)
+		return set(doc, path[:len(path)-1], node)
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// set replaces the array at path, which add and remove reallocate.
+func set(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		i, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+	default:
+		return nil, ErrPathNotFound
+	}
+	return doc, nil
+}
+
+// arrayIndex parses an array index token no greater than limit. Leading zeros
+// are not allowed.
+func arrayIndex(token string, limit int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > limit {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for name, item := range v {
+			c[name] = clone(item)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = clone(item)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// equal compares two JSON values structurally. Numbers are equal when they
+// denote the same value, so 1, 1.0 and 1e0 match at any depth, and object
+// members are compared regardless of order.
+func equal(a, b any) bool {
+	switch av := a.(type) {
+	case json.Number:
+		bv, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		ar, aok := new(big.Rat).SetString(av.String())
+		br, bok := new(big.Rat).SetString(bv.String())
+		return aok && bok && ar.Cmp(br) == 0
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for name, item := range av {
+			other, ok := bv[name]
+			if !ok || !equal(item, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		// Strings, booleans and null.
+		return a == b
+	}
+}
@@ -0,0 +1,278 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// normalize re-encodes a JSON value so documents that differ only in member
+// order or whitespace compare equal as strings.
+func normalize(t *testing.T, data string) string {
+	t.Helper()
+	value, err := decode([]byte(data))
+	if err != nil {
+		t.Fatalf("decode %s: %v", data, err)
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("encode %s: %v", data, err)
+	}
+	return string(out)
+}
+
+// RFC 6902 Appendix A, followed by cases the appendix does not cover.
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+			want:  `{"foo": "bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:  `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "A.8 testing a value: success",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			want: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:    "A.9 testing a value: error",
+			doc:     `{"baz": "qux"}`,
+			patch:   `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:  `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:    "A.12 adding to a nonexistent target",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "A.13 invalid JSON patch document",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:  "A.14 ~ escape ordering",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": 10}]`,
+			want:  `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:    "A.15 comparing strings and numbers",
+			doc:     `{"/": 9, "~1": 10}`,
+			patch:   `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:  `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			name:  "replacing the whole document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "", "value": {"baz": 1}}]`,
+			want:  `{"baz": 1}`,
+		},
+		{
+			name:    "replacing a missing member",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "replace", "path": "/baz", "value": 1}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:  "replacing an array element",
+			doc:   `{"foo": [1, 2, 3]}`,
+			patch: `[{"op": "replace", "path": "/foo/2", "value": 4}]`,
+			want:  `{"foo": [1, 2, 4]}`,
+		},
+		{
+			name:  "testing nested numbers written differently",
+			doc:   `{"a": {"b": [1.0, 2e1], "c": 100000000000000000001}}`,
+			patch: `[{"op": "test", "path": "/a", "value": {"c": 100000000000000000001, "b": [1, 20]}}]`,
+			want:  `{"a": {"b": [1.0, 2e1], "c": 100000000000000000001}}`,
+		},
+		{
+			name:    "testing large integers that differ",
+			doc:     `{"a": 100000000000000000001}`,
+			patch:   `[{"op": "test", "path": "/a", "value": 100000000000000000000}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "testing arrays of different length",
+			doc:     `{"a": [1, 2]}`,
+			patch:   `[{"op": "test", "path": "/a", "value": [1, 2, 3]}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "copying a value",
+			doc:   `{"a": {"b": [1]}}`,
+			patch: `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/b/-", "value": 2}]`,
+			want:  `{"a": {"b": [1]}, "c": {"b": [1, 2]}}`,
+		},
+		{
+			name:    "moving a value into its own child",
+			doc:     `{"a": {"b": 1}}`,
+			patch:   `[{"op": "move", "from": "/a", "path": "/a/c"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "array index with a leading zero",
+			doc:     `{"a": [1, 2]}`,
+			patch:   `[{"op": "remove", "path": "/a/01"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{}`,
+			patch:   `[{"op": "merge", "path": "/a", "value": 1}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing path",
+			doc:     `{}`,
+			patch:   `[{"op": "add", "value": 1}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "failed operation discards earlier ones",
+			doc:     `{"a": 1}`,
+			patch:   `[{"op": "remove", "path": "/a"}, {"op": "test", "path": "/a", "value": 1}]`,
+			wantErr: ErrPathNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if normalize(t, string(got)) != normalize(t, tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// RFC 7386 Appendix A.
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" "+tt.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if normalize(t, string(got)) != normalize(t, tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMediaType(t *testing.T) {
+	doc := []byte(`{"a": 1}`)
+
+	got, err := Apply("application/merge-patch+json; charset=utf-8", doc, []byte(`{"a": 2}`))
+	if err != nil || normalize(t, string(got)) != `{"a":2}` {
+		t.Errorf("merge patch = %s, %v", got, err)
+	}
+
+	got, err = Apply(MediaTypeJSONPatch, doc, []byte(`[{"op": "replace", "path": "/a", "value": 3}]`))
+	if err != nil || normalize(t, string(got)) != `{"a":3}` {
+		t.Errorf("json patch = %s, %v", got, err)
+	}
+
+	if _, err := Apply("application/json", doc, []byte(`{}`)); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("plain json err = %v, want %v", err, ErrUnsupportedMediaType)
+	}
+}
@@ -4,9 +4,14 @@ db = db.getSiblingDB("budget_tracker");
 // Create collections
 db.createCollection("users");
 db.createCollection("budgets");
+db.createCollection("categories");
 db.createCollection("expenses");
 db.createCollection("alerts");
 db.createCollection("alert_notifications");
+db.createCollection("outbox_events");
+db.createCollection("refresh_tokens");
+db.createCollection("recurring_expenses");
+db.createCollection("reconciliation_reports");
 
 // Create indexes
 db.users.createIndex({ email: 1 }, { unique: true });
@@ -14,14 +19,58 @@ db.users.createIndex({ reset_token: 1 });
 
 db.budgets.createIndex({ user_id: 1, start_date: -1 });
 db.budgets.createIndex({ is_active: 1, end_date: 1 });
+db.budgets.createIndex({ "members.user_id": 1 });
+db.budgets.createIndex({ "categories.category_id": 1 });
+db.budgets.createIndex(
+  { previous_budget_id: 1 },
+  { unique: true, partialFilterExpression: { previous_budget_id: { $exists: true } } }
+);
+
+db.categories.createIndex({ user_id: 1, key: 1 }, { unique: true });
+
+db.budget_templates.createIndex({ user_id: 1 });
+
+db.budget_invitations.createIndex({ token: 1 }, { unique: true });
+db.budget_invitations.createIndex({ budget_id: 1, status: 1 });
+db.budget_invitations.createIndex({ email: 1, status: 1 });
+
+db.envelope_transfers.createIndex({ budget_id: 1, created_at: -1 });
 
 db.expenses.createIndex({ budget_id: 1, date: -1 });
 db.expenses.createIndex({ user_id: 1, date: -1 });
+db.expenses.createIndex({ created_by: 1, date: -1 });
+db.expenses.createIndex({ category_id: 1 });
+db.expenses.createIndex(
+  { recurring_expense_id: 1, occurrence_date: 1 },
+  { unique: true, partialFilterExpression: { recurring_expense_id: { $exists: true } } }
+);
 
 db.alerts.createIndex({ user_id: 1 });
 db.alerts.createIndex({ budget_id: 1 });
 db.alerts.createIndex({ is_enabled: 1 });
 
 db.alert_notifications.createIndex({ user_id: 1, sent_at: -1 });
+db.alert_notifications.createIndex({ user_id: 1, channel: 1, sent_at: -1 });
+db.alert_notifications.createIndex({ user_id: 1, channel: 1, read_at: 1, sent_at: -1 });
+
+db.outbox_events.createIndex({ processed_at: 1, occurred_at: 1 });
+db.outbox_events.createIndex({ dead_at: 1 }, { sparse: true });
+
+db.goals.createIndex({ user_id: 1 });
+db.goals.createIndex({ target_date: 1 });
+
+db.goal_contributions.createIndex({ goal_id: 1, date: -1 });
+
+db.recurring_expenses.createIndex({ user_id: 1 });
+db.recurring_expenses.createIndex({ is_active: 1, next_occurrence: 1 });
+
+db.reconciliation_reports.createIndex({ started_at: -1 });
+
+db.exchange_rates.createIndex({ base: 1, quote: 1, date: -1 }, { unique: true });
+
+db.refresh_tokens.createIndex({ token_id: 1 }, { unique: true });
+db.refresh_tokens.createIndex({ family_id: 1 });
+db.refresh_tokens.createIndex({ user_id: 1 });
+db.refresh_tokens.createIndex({ expires_at: 1 }, { expireAfterSeconds: 0 });
 
 print("Database initialized successfully");